@@ -0,0 +1,154 @@
+package kp
+
+import (
+    "fmt"
+    "math"
+    "math/rand"
+)
+
+// Parameters of the simulated annealing heuristic
+type AnnealParams struct {
+    Iterations int	// iteration budget (number of moves), default: 100000
+    T0         float64	// initial temperature, default: maximal profit of an item
+    Cooling    string	// cooling schedule for iteration k, default: "geometric"
+			// "geometric"   : T = T0 * Alpha^k
+			// "linear"      : T = T0 * (1 - k/Iterations)
+			// "logarithmic" : T = T0 / ln(k+e)
+    Alpha      float64	// cooling factor for geometric cooling,
+			// default: the temperature drops to T0/1000 within the budget
+    Repair     string	// repair operator for infeasible moves, default: "ratio"
+			// "ratio"  : remove items with least profit/weight until feasible
+			// "random" : remove random items until feasible
+			// "reject" : reject infeasible moves
+    Seed       int64	// seed value for the random generator, 0: time based seed
+}
+
+// Solve a knapsack problem by simulated annealing.
+//
+// We start with the solution of Greedy(). A move flips the decision of a
+// random item. If the knapsack overflows, the repair operator removes other
+// items or rejects the move. A move that changes the objective function value
+// by d is accepted if d >= 0 or with probability exp(d/T) if d < 0.
+// The best solution found within the iteration budget is returned.
+//
+// The "ratio" repair operator assumes sorted items (see kp.CheckSortedItems()),
+// otherwise it works, too, but removes arbitrary items.
+func SimAnneal(kp KnapsackProblem, par AnnealParams) ([]int,int,error) {
+    if r, err := fixedReduction(kp); r != nil || err != nil {	// fixed items: solve the reduced problem
+	if err != nil {
+	    return nil,0,err
+	}
+	return solveReduced(r, func(p KnapsackProblem) ([]int,int,error) { return SimAnneal(p, par) })
+    }
+
+    n := kp.N()
+    c := kp.Capacity()
+
+    if par.Iterations <= 0 {
+	par.Iterations = 100000
+    }
+    if par.T0 <= 0 {
+	for i:=0 ; i<n ; i++ {
+	    par.T0 = math.Max(par.T0, float64(kp.Profit(i)))
+	}
+    }
+    if par.Cooling == "" {
+	par.Cooling = "geometric"
+    }
+    if par.Alpha <= 0 || par.Alpha >= 1 {
+	par.Alpha = math.Pow(0.001, 1.0/float64(par.Iterations))
+    }
+    if par.Repair == "" {
+	par.Repair = "ratio"
+    }
+    par.Seed = randomSeed(par.Seed)
+
+    var temp func(k int) float64		// temperature of iteration k
+    switch par.Cooling {
+    case "geometric":
+	t := par.T0
+	temp = func(k int) float64 { t *= par.Alpha; return t }
+    case "linear":
+	temp = func(k int) float64 { return par.T0 * (1 - float64(k)/float64(par.Iterations)) }
+    case "logarithmic":
+	temp = func(k int) float64 { return par.T0 / math.Log(float64(k)+math.E) }
+    default:
+	return nil, 0, fmt.Errorf("unknown cooling schedule: %s", par.Cooling)
+    }
+    if par.Repair != "ratio" && par.Repair != "random" && par.Repair != "reject" {
+	return nil, 0, fmt.Errorf("unknown repair operator: %s", par.Repair)
+    }
+
+    x,z := Greedy(kp)				// start solution
+    xbest := make([]int,n)			// best solution so far
+    copy(xbest,x)
+    zbest := z
+    if n == 0 {
+	return xbest,zbest,nil
+    }
+    w := 0
+    for i:=0 ; i<n ; i++ {
+	w += x[i]*kp.Weight(i)
+    }
+
+    rnd := rand.New(rand.NewSource(par.Seed))
+    var removed []int				// items removed by the repair operator
+    for k:=0 ; k<par.Iterations ; k++ {
+	t := temp(k)
+	j := rnd.Intn(n)			// the move: flip item j
+	removed = removed[:0]
+	delta := 0
+	wnew := w
+	if x[j] == 1 {
+	    delta = -kp.Profit(j)
+	    wnew -= kp.Weight(j)
+	} else {
+	    delta = kp.Profit(j)
+	    wnew += kp.Weight(j)
+	    if wnew > c {			// knapsack overflows: repair
+		if par.Repair == "reject" || kp.Weight(j) > c {
+		    continue
+		}
+		for wnew > c {			// remove items other than j
+		    i := n-1
+		    if par.Repair == "ratio" {
+			for ; x[i] == 0 || i == j || contains(removed, i) ; i-- {
+			}
+		    } else {
+			for i = rnd.Intn(n) ; x[i] == 0 || i == j || contains(removed, i) ; i = rnd.Intn(n) {
+			}
+		    }
+		    removed = append(removed, i)
+		    delta -= kp.Profit(i)
+		    wnew -= kp.Weight(i)
+		}
+	    }
+	}
+
+	if delta < 0 && (t <= 0 || rnd.Float64() >= math.Exp(float64(delta)/t)) {
+	    continue				// move rejected
+	}
+	x[j] = 1 - x[j]				// move accepted
+	for _,i := range removed {
+	    x[i] = 0
+	}
+	z += delta
+	w = wnew
+	if z > zbest {				// new best solution
+	    copy(xbest,x)
+	    zbest = z
+	}
+    }
+
+    return xbest,zbest,nil
+}
+
+// Is i an element of a?
+func contains(a []int, i int) bool {
+    for _,j := range a {
+	if i == j {
+	    return true
+	}
+    }
+    return false
+}
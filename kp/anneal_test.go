@@ -0,0 +1,39 @@
+package kp
+
+import (
+    "testing"
+)
+
+func TestSimAnneal(t *testing.T) {
+    params := []AnnealParams{
+	{ Iterations: 2000, Seed: 1 },
+	{ Iterations: 2000, Cooling: "linear", Repair: "random", Seed: 7 },
+	{ Iterations: 2000, Cooling: "logarithmic", Repair: "reject", Seed: 11 },
+	{ Iterations: 2000, T0: 5, Alpha: 0.99, Seed: 13 },
+    }
+    for _,kp := range testProblems(t) {
+	_,zg := Greedy(kp)
+	for _,par := range params {
+	    x,z,err := SimAnneal(kp, par)
+	    if err != nil {
+		t.Fatal(err)
+	    }
+	    checkFeasible(t, "sa", kp, x, z)
+	    if z < zg {
+		t.Errorf("%s, %+v: z = %v is worse than the start %v", kp.Name, par, z, zg)
+	    }
+	    if _,z2,_ := SimAnneal(kp, par); z2 != z {
+		t.Errorf("%s, %+v: not reproducible, z = %v and %v", kp.Name, par, z, z2)
+	    }
+	}
+    }
+}
+
+func TestSimAnnealParams(t *testing.T) {
+    kp := testProblems(t)[3]
+    for _,par := range []AnnealParams{ { Cooling: "exponential" }, { Repair: "drop" } } {
+	if _,_,err := SimAnneal(kp, par); err == nil {
+	    t.Errorf("%+v: no error", par)
+	}
+    }
+}
@@ -1,5 +1,10 @@
 package kp
 
+import (
+    "errors"
+    "time"
+)
+
 // A state in the branch and bound process.
 type stateT struct {
     decision int	// decision(=1 or =0)
@@ -11,23 +16,104 @@ type stateT struct {
     father   *stateT	// preceeding state
 }
 
+// Options for the branch and bound algorithms.
+type BabOptions struct {
+    CheckpointFile     string		// file for checkpoints, no checkpoints if empty
+    CheckpointInterval time.Duration	// time between two checkpoints
+    Resume             bool		// resume the search from CheckpointFile, the incumbent
+					// of the checkpoint replaces Incumbent and NoWarmStart
+    Incumbent          []int		// initial incumbent, default: solution of ExtGreedy()
+    NoWarmStart        bool		// start without incumbent (pmax = 0)
+    Stats              *BabStats	// if not nil, statistics of the run are stored here
+    Certificate        *Certificate	// if not nil, an optimality certificate is stored here
+    ExactFill          bool		// only solutions which fill the capacity exactly
+}
+
+// Statistics of a branch and bound run
+type BabStats struct {
+    Nodes int64		// number of generated states (put on the agenda)
+    Z0    int		// value of the initial incumbent
+}
+
 // Solve a knapsack problem by Branch and Bound.
 // Here we use a best upper bound strategy which leads to an A*-algorithm.
 // This is simply achieved by using a priority queue as agenda.
 func BranchAndBound(kp KnapsackProblem) ([]int,int) {
+    x,z,_ := BranchAndBoundOpt(kp, BabOptions{})	// the only error without checkpoints: fixed items
+							// don't fit, then x is nil
+    return x,z
+}
+
+// Same as BranchAndBound(), but with options:
+// The agenda may be written to a checkpoint file periodically and the search
+// may be resumed from such a checkpoint.
+// States whose upper bound doesn't exceed the value of the initial incumbent
+// (warm start) are not put on the agenda. If the agenda runs empty, the
+// incumbent is optimal.
+// If opt.Certificate is set, the search tree is stored as certificate: its
+// leaves are the pruned states, the states left on the agenda and the goal state.
+// Observe: A* never expands states with an upper bound below the optimal value,
+// so the warm start mainly saves memory. The number of generated states may
+// even grow slightly, because the order of states with equal upper bounds
+// in the priority queue changes.
+// If opt.ExactFill is set, goal states which don't fill the capacity exactly
+// are discarded and an error is returned, if there is no such goal state.
+func BranchAndBoundOpt(kp KnapsackProblem, opt BabOptions) ([]int,int,error) {
     var (
         state1 *stateT
         state2 *stateT
     )
 
+    if r, err := fixedReduction(kp); r != nil || err != nil {	// fixed items: solve the reduced problem
+	if err != nil {
+	    return nil,0,err
+	}
+	return solveReducedBab(r, opt, BranchAndBoundOpt)
+    }
+
+    run, err := startRun(kp, opt, "bab")	// initial agenda and incumbent
+    if err != nil {				// or those of the checkpoint
+	return nil,0,err
+    }
+    agenda := run.agenda
+    xinc, zinc := run.incumbent, run.z0
+    nodes := run.nodes				// number of generated states
     n := kp.N()					// number of items
-    agenda := []*stateT{ initialState(kp) }	// initial state of our agenda
+    cp := newCheckpointer(opt)
+    tree, err := newTreeRecorder(opt)
+    if err != nil {
+	return nil,0,err
+    }
 
     for {
+	if len(agenda) == 0 {		// all states pruned: the incumbent is optimal
+	    opt.Stats.store(nodes, zinc)
+	    tree.store()
+	    if xinc == nil {		// exact fill without solution
+		return nil,0,errNoExactFill
+	    }
+	    return xinc,zinc,nil
+	}
+	if cp.due() {				// time for a checkpoint?
+	    err := cp.write(kp, "bab", &checkpointT{ agenda: agenda, pmax: zinc, incumbent: xinc, z0: zinc,
+						     exactFill: opt.ExactFill, nodes: nodes })
+	    if err != nil {
+	        return nil,0,err
+	    }
+	}
         state := agenda[0]		// get the first element of the agenda (priority queue)
+	if state.nitems == n && opt.ExactFill && state.capacity != 0 {
+	    agenda = pqUpdate(agenda,nil,nil)	// goal state doesn't fill the knapsack
+	    continue
+	}
 	if state.nitems == n {		// goal state: optimal solution found
-	    return optSol(kp, state)	// store it in kp
-					// and we are done.
+	    opt.Stats.store(nodes, zinc)
+	    for _,s := range agenda {	// the goal and the remaining states are leaves
+		tree.leaf(s)
+	    }
+	    tree.store()
+	    x,z := optSol(kp, state)	// store it in kp
+	    return x,z,nil		// and we are done.
 	}
 						// no goal state: nitems < n
 	if state.capacity >= kp.Weight(state.nitems) {	// is X[item]=1 feasible? if yes:
@@ -36,6 +122,15 @@ func BranchAndBound(kp KnapsackProblem) ([]int,int) {
 	    state1 = nil
 	}
 	state2 = successor0(kp,state)		// successor for X[item] = 0
+	if state1 != nil && state1.phi <= zinc {	// prune successors which
+	    tree.leaf(state1)				// can't beat the incumbent
+	    state1 = nil
+	}
+	if state2.phi <= zinc {
+	    tree.leaf(state2)
+	    state2 = nil
+	}
+	nodes += countStates(state1, state2)
 	agenda = pqUpdate(agenda,state1,state2)		// update the agenda
     }
 }
@@ -47,34 +142,193 @@ func BranchAndBound(kp KnapsackProblem) ([]int,int) {
 // The garbage collector should keep the used memory small, because the agenda
 // contains only one path (with sibling nodes, the size of the agenda is bounded by 2n+1).
 func BranchAndBoundHS(kp KnapsackProblem) ([]int,int) {
-    var (
-	stateB *stateT				// actual best solution
-    )
+    x,z,_ := BranchAndBoundHSOpt(kp, BabOptions{})	// the only error without checkpoints: fixed items
+							// don't fit, then x is nil
+    return x,z
+}
+
+// Same as BranchAndBoundHS(), but with options:
+// The agenda and the actual best solution may be written to a checkpoint file
+// periodically and the search may be resumed from such a checkpoint.
+// The search starts with the value of the initial incumbent (warm start) as
+// actual best solution value. If no better solution is found, the incumbent
+// is optimal.
+// If opt.Certificate is set, the search tree is stored as certificate: its
+// leaves are the goal states and the pruned states.
+// If opt.ExactFill is set, only goal states which fill the capacity exactly
+// are solutions and an error is returned, if there is no such goal state.
+func BranchAndBoundHSOpt(kp KnapsackProblem, opt BabOptions) ([]int,int,error) {
+    if r, err := fixedReduction(kp); r != nil || err != nil {	// fixed items: solve the reduced problem
+	if err != nil {
+	    return nil,0,err
+	}
+	return solveReducedBab(r, opt, BranchAndBoundHSOpt)
+    }
+
+    run, err := startRun(kp, opt, "hs")		// initial agenda and incumbent
+    if err != nil {				// or those of the checkpoint
+	return nil,0,err
+    }
+    x,z,err := hsSearch(kp, opt, run, -1)
+    if err != nil {
+	return nil,0,err
+    }
+    if x == nil {				// no better solution found
+	if run.incumbent == nil {		// exact fill without solution
+	    return nil,0,errNoExactFill
+	}
+	return run.incumbent,run.z0,nil
+    }
+    return x,z,nil
+}
 
+// The depth first search of BranchAndBoundHS(), started from the agenda and
+// the actual best solution of run.
+// Only goal states with a profit larger than pmax are stored as solutions.
+// So if we know a solution with value z, pmax = z-1 prunes all states with an
+// upper bound below z and the search still finds the same optimal solution as
+// with pmax = 0 (the first optimal goal state in depth first order).
+// If there is no goal state with a profit larger than pmax, x is nil.
+// If an upper bound zmax >= 0 of the optimal value is known, the search stops
+// at the first goal state with profit zmax: it is optimal and the same goal
+// state as without zmax, because no goal state later in depth first order
+// replaces it.
+func hsSearch(kp KnapsackProblem, opt BabOptions, run *checkpointT, zmax int) ([]int,int,error) {
+    stateB := run.best				// actual best solution
+    pmax := run.pmax
+    nodes := run.nodes				// number of generated states
     n := kp.N()					// number of items
-    pmax := 0					// actual best solution value
-    agenda := []*stateT{ initialState(kp) }	// initial state of our agenda
+    agenda := run.agenda
+    cp := newCheckpointer(opt)
+    tree, err := newTreeRecorder(opt)
+    if err != nil {
+	return nil,0,err
+    }
 
     for {
         if len(agenda) == 0 {			// if the agenda is empty we are done.
-	    return optSol(kp, stateB)		// we store the best solution we found
+	    opt.Stats.store(nodes, run.z0)
+	    tree.store()
+	    if stateB == nil {			// no solution better than pmax
+		return nil,pmax,nil
+	    }
+	    x,z := optSol(kp, stateB)		// we store the best solution we found
+	    return x,z,nil
+	}
+	if cp.due() {				// time for a checkpoint?
+	    err := cp.write(kp, "hs", &checkpointT{ agenda: agenda, best: stateB, pmax: pmax, incumbent: run.incumbent,
+						    z0: run.z0, exactFill: opt.ExactFill, nodes: nodes })
+	    if err != nil {
+	        return nil,0,err
+	    }
 	}
 	state := agenda[len(agenda)-1]		// take the top of the stack
 	agenda = agenda[0:len(agenda)-1]	// pop
 	if state.nitems == n {			// popped state is a goal state
-	    if state.psum > pmax {		// new best solution? if yes
+	    tree.leaf(state)
+	    if state.psum > pmax && (!opt.ExactFill || state.capacity == 0) {	// new best solution? if yes
 		pmax = state.psum		// store new best solution value
 	        stateB = state			// and pointer to this solution
+		if pmax == zmax {		// the upper bound is reached
+		    agenda = nil
+		}
 	    }
-	} else if state.phi > pmax {		// not a goal state but upper bound larger
+	} else if state.phi <= pmax {		// pruned state
+	    tree.leaf(state)
+	} else {				// not a goal state but upper bound larger
 	    agenda = append(agenda,successor0(kp,state))	// push for decision = 0
+	    nodes++
 	    if state.capacity >= kp.Weight(state.nitems) {// if residual capacity is large enough
 		agenda = append(agenda,successor1(kp,state))	// push for decision = 1
+		nodes++
 	    }
 	}
     }
 }
 
+// Start of a branch and bound run: the initial state and incumbent, or the
+// agenda, actual best solution and incumbent of the checkpoint, if the run is
+// resumed. Without a best solution, pmax is the value of the incumbent.
+func startRun(kp KnapsackProblem, opt BabOptions, alg string) (*checkpointT,error) {
+    if opt.Resume {
+	return readCheckpoint(kp, opt.CheckpointFile, alg, opt.ExactFill)
+    }
+    x,z,err := incumbent(kp, opt)
+    if err != nil {
+	return nil,err
+    }
+    return &checkpointT{ agenda: []*stateT{ initialState(kp) }, pmax: z, incumbent: x, z0: z,
+			 exactFill: opt.ExactFill }, nil
+}
+
+// Initial incumbent of the branch and bound algorithms: opt.Incumbent,
+// the solution of ExtGreedy() or the empty knapsack if opt.NoWarmStart is set.
+// With opt.ExactFill there is no incumbent (nil, value -1), unless
+// opt.Incumbent is given and fills the capacity exactly.
+func incumbent(kp KnapsackProblem, opt BabOptions) ([]int,int,error) {
+    n := kp.N()
+    if opt.ExactFill && (opt.NoWarmStart || opt.Incumbent == nil) {
+	return nil, -1, nil
+    }
+    if opt.NoWarmStart {
+	return make([]int,n), 0, nil
+    }
+    if opt.Incumbent == nil {
+	x,z := ExtGreedy(kp)
+	return x,z,nil
+    }
+
+    z,err := CheckIncumbent(kp, opt.Incumbent, opt.ExactFill)
+    if err != nil {
+	return nil, 0, err
+    }
+    return append([]int(nil), opt.Incumbent...), z, nil
+}
+
+// Check that x can be used as initial incumbent of the branch and bound
+// algorithms (see BabOptions): a binary vector of the problem size which fits
+// into the knapsack (with exactFill: which fills the capacity exactly).
+// The result is the objective function value of x.
+func CheckIncumbent(kp KnapsackProblem, x []int, exactFill bool) (int,error) {
+    n := kp.N()
+    if err := checkBinary(x, n, "incumbent"); err != nil {
+	return 0, err
+    }
+    z := 0
+    w := 0
+    for i:=0 ; i<n ; i++ {
+	z += x[i]*kp.Profit(i)
+	w += x[i]*kp.Weight(i)
+    }
+    if w > kp.Capacity() {
+	return 0, errors.New("incumbent is infeasible")
+    }
+    if exactFill && w != kp.Capacity() {
+	return 0, errors.New("incumbent doesn't fill the capacity exactly")
+    }
+    return z,nil
+}
+
+// Number of states != nil.
+func countStates(s1 *stateT, s2 *stateT) int64 {
+    n := int64(0)
+    if s1 != nil {
+	n++
+    }
+    if s2 != nil {
+	n++
+    }
+    return n
+}
+
+// Store the statistics of a run, if stats is not nil.
+func (stats *BabStats) store(nodes int64, z0 int) {
+    if stats != nil {
+	stats.Nodes = nodes
+	stats.Z0 = z0
+    }
+}
+
 func initialState(kp KnapsackProblem) *stateT {
     state := &stateT{			// initial state
 	decision : -1,			// no decision
@@ -131,7 +385,7 @@ func optSol(kp KnapsackProblem, state *stateT) ([]int,int) {
 // Update the agenda, which is organized as a max-heap.
 // s1 and s2 are the successor states of pq[0].
 // s1 results from decision = 1 and may be nil (if decision = 1 is infeasible).
-// s2 results from decision = 0 and is alwas != nil.
+// s2 results from decision = 0 and may be nil, too (if it is pruned).
 // The max-heap is a left fully binary tree organized in an array.
 // The root (largest element) is at index 0.
 // A state at index i has its left and right son at index 2i+1 resp. 2i+2.
@@ -141,14 +395,21 @@ func pqUpdate(pq []*stateT, s1 *stateT, s2 *stateT) []*stateT {
     )
 
     if s1 != nil {		// s1 (decision = 1) maybe nil
-        s = s1			// s2 (decision = 0) is always != nil
+	s = s1			// s2 (decision = 0) maybe nil, too
     } else {
         s = s2
     }
+    if s == nil {		// no successor: the agenda head is removed
+	last := len(pq)-1	// and substituted by the last state
+	pq[0] = pq[last]
+	pq = pq[:last]
+	reheapTop(pq)
+	return pq
+    }
     pq[0] = s			// the first state != nil substitutes the agenda head
     reheapTop(pq)		// heap property maybe violated ==> reconstitute the heap property
 
-    if s1 != nil {		// an eventually second state is appended
+    if s1 != nil && s2 != nil { // an eventually second state is appended
         pq = append(pq, s2)
 	reheapBottom(pq)	// reconstitute the heap property
     }
@@ -0,0 +1,81 @@
+package kp
+
+import (
+    "testing"
+)
+
+var babSolvers = []struct {
+    name string
+    bab  func(KnapsackProblem, BabOptions) ([]int,int,error)
+}{
+    { "bab", BranchAndBoundOpt },
+    { "hs", BranchAndBoundHSOpt },
+}
+
+func TestWarmStart(t *testing.T) {
+    for _,kp := range testProblems(t) {
+	xopt,zopt := DynProg(kp)
+	xg,_ := Greedy(kp)
+	for _,s := range babSolvers {
+	    var cold BabStats
+
+	    x,z,err := s.bab(kp, BabOptions{ NoWarmStart: true, Stats: &cold })
+	    if err != nil {
+		t.Fatal(err)
+	    }
+	    checkOptimal(t, s.name + " without warm start", kp, x, z)
+	    if cold.Z0 != 0 {
+		t.Errorf("%s, %s: Z0 = %v without warm start", s.name, kp.Name, cold.Z0)
+	    }
+
+	    for _,inc := range [][]int{ nil, make([]int, kp.N()), xg, xopt } {
+		var stats BabStats
+
+		x,z,err = s.bab(kp, BabOptions{ Incumbent: inc, Stats: &stats })
+		if err != nil {
+		    t.Fatal(err)
+		}
+		checkOptimal(t, s.name, kp, x, z)
+		if inc != nil && stats.Z0 != objective(kp, inc) {
+		    t.Errorf("%s, %s: Z0 = %v, but the incumbent has the value %v", s.name, kp.Name, stats.Z0, objective(kp, inc))
+		}
+		// The warm start only prunes states, so the depth first search
+		// generates less states. The best first search may break ties of
+		// the upper bounds differently then, unless the incumbent is optimal.
+		if (s.name == "hs" || stats.Z0 == zopt) && stats.Nodes > cold.Nodes {
+		    t.Errorf("%s, %s, incumbent %v: %v nodes with and %v without warm start", s.name, kp.Name, inc, stats.Nodes, cold.Nodes)
+		}
+	    }
+	}
+    }
+}
+
+func TestCheckIncumbent(t *testing.T) {
+    kp := KnapsackData{ Name: "incumbent", Dim: 3, P: []int{6,10,12}, W: []int{1,2,3}, C: 5 }
+    tests := []struct {
+	x         []int
+	exactFill bool
+	z         int
+	ok        bool
+    }{
+	{ []int{0,0,0}, false, 0, true },
+	{ []int{1,1,0}, false, 16, true },
+	{ []int{0,1,1}, true, 22, true },
+	{ []int{1,1}, false, 0, false },		// wrong size
+	{ []int{0,2,0}, false, 0, false },		// not binary
+	{ []int{1,1,1}, false, 0, false },		// infeasible
+	{ []int{1,1,0}, true, 0, false },		// doesn't fill the capacity
+    }
+    for _,test := range tests {
+	z,err := CheckIncumbent(kp, test.x, test.exactFill)
+	if (err == nil) != test.ok || z != test.z {
+	    t.Errorf("%v, exact fill %v: z = %v, error %v", test.x, test.exactFill, z, err)
+	}
+	for _,s := range babSolvers {		// the solvers reject invalid incumbents
+	    _,_,err = s.bab(kp, BabOptions{ Incumbent: test.x, ExactFill: test.exactFill })
+	    if (err == nil) != test.ok {
+		t.Errorf("%s, %v, exact fill %v: error %v", s.name, test.x, test.exactFill, err)
+	    }
+	}
+    }
+}
@@ -0,0 +1,189 @@
+package kp
+
+import (
+    "errors"
+)
+
+// Bounded knapsack problem: up to Bound(i) copies of item i may be selected,
+// i.e. X[i] is an integer in [0,Bound(i)].
+type BoundedKnapsackProblem interface {
+    KnapsackProblem
+    Bound(i int) int			// maximal number of copies of item i
+}
+
+// Upper bound of the multiplicity of item i. Without bounds every item
+// exists only once (0/1 knapsack problem).
+func (kp KnapsackData) Bound(i int) int {
+    if kp.Bounds == nil {
+	return 1
+    }
+    return kp.Bounds[i]
+}
+
+// Check the bounds of a bounded knapsack problem.
+func CheckBounds(kp *KnapsackData) error {
+    if kp.Bounds == nil {
+	return nil
+    }
+    if len(kp.Bounds) != kp.Dim {
+	return errors.New("wrong input: bounds and problem have different sizes")
+    }
+    for _,b := range kp.Bounds {
+	if b < 0 {
+	    return errors.New("wrong input: negative bound")
+	}
+    }
+    return nil
+}
+
+// Solve a bounded knapsack problem with dynamic programming.
+//
+// Like DynProg(), but v[i][s] is the maximum of
+// Profit[i]*k + v[i+1][s-k*Weight[i]] for k=0,...,Bound[i].
+// For a fixed residue r = s mod Weight[i] this is the maximum over a sliding
+// window of the values v[i+1][r+t*Weight[i]] - t*Profit[i], which we maintain
+// in a monotone queue. So we need O(n*Capacity) time independent of the bounds.
+// The solution x contains the number of copies of each item.
+func BoundedDynProg(kp BoundedKnapsackProblem) ([]int,int,error) {
+    var (
+	v  []int			// value function for item i
+	vv []int			// value function for item i+1
+    )
+
+    if err := noFixedItems(kp); err != nil {
+	return nil,0,err
+    }
+
+    n := kp.N()
+    x := make([]int,n)
+    c := kp.Capacity()
+
+    policy := makePolicyTable(n, c)	// policy[i][s] stores the optimal number of
+					// copies of item i for rest capacity s
+    vv = make([]int,c+1)
+    q := make([]int,c+1)		// monotone queue of multiples t
+
+    // Backward computation
+    for i:=n-1 ; i>=0 ; i-- {
+	p, w, b := kp.Profit(i), kp.Weight(i), kp.Bound(i)
+	v = make([]int, c+1)
+	if w == 0 {			// weightless items: all copies if profitable
+	    for s:=0 ; s<=c ; s++ {
+		v[s] = vv[s]
+		if p > 0 {
+		    v[s] += b*p
+		    policy[i][s] = b
+		}
+	    }
+	    vv = v
+	    continue
+	}
+	for r:=0 ; r<w && r<=c ; r++ {	// residue classes s = r + j*w
+	    head, tail := 0, 0
+	    for j:=0 ; r+j*w<=c ; j++ {
+		g := vv[r+j*w] - j*p		// value of t = j
+		for tail > head && vv[r+q[tail-1]*w] - q[tail-1]*p <= g {
+		    tail--			// on ties we keep the larger t (less copies)
+		}
+		q[tail] = j
+		tail++
+		if q[head] < j-b {		// at most b copies
+		    head++
+		}
+		t := q[head]
+		v[r+j*w] = vv[r+t*w] + (j-t)*p
+		policy[i][r+j*w] = j-t
+	    }
+	}
+	vv = v
+    }
+
+    // Forward computation
+    z := vv[c]
+    s := c
+    for i:=0 ; i<n ; i++ {
+	x[i] = policy[i][s]
+	s -= x[i]*kp.Weight(i)
+    }
+
+    return x,z,nil
+}
+
+// A bounded knapsack problem transformed into a 0/1 knapsack problem by
+// binary splitting: item i with bound b is split into pieces of 1, 2, 4, ...
+// copies and a remaining piece, such that every number of copies in [0,b]
+// is the sum of some pieces. So we get O(log b) pieces instead of b copies.
+//
+// The pieces of an item have the same profit/weight ratio as the item and
+// they are consecutive. Hence the 0/1 problem satisfies the precondition
+// of sorted items if the bounded problem does.
+type Splitting struct {
+    kp   BoundedKnapsackProblem	// the bounded problem
+    item []int			// item of each piece
+    mult []int			// number of copies in each piece
+}
+
+// Split the items of a bounded knapsack problem into pieces.
+func SplitBounded(kp BoundedKnapsackProblem) *Splitting {
+    sp := &Splitting{ kp: kp }
+    for i:=0 ; i<kp.N() ; i++ {
+	b := kp.Bound(i)
+	for k:=1 ; b>0 ; k*=2 {
+	    m := k
+	    if m > b {
+		m = b
+	    }
+	    sp.item = append(sp.item, i)
+	    sp.mult = append(sp.mult, m)
+	    b -= m
+	}
+    }
+    return sp
+}
+
+func (sp *Splitting) N() int {
+    return len(sp.item)
+}
+
+func (sp *Splitting) Capacity() int {
+    return sp.kp.Capacity()
+}
+
+func (sp *Splitting) Profit(j int) int {
+    return sp.mult[j] * sp.kp.Profit(sp.item[j])
+}
+
+func (sp *Splitting) Weight(j int) int {
+    return sp.mult[j] * sp.kp.Weight(sp.item[j])
+}
+
+// Number of copies of each item for a solution x of the 0/1 problem.
+func (sp *Splitting) Expand(x []int) []int {
+    y := make([]int, sp.kp.N())
+    for j,i := range sp.item {
+	y[i] += x[j]*sp.mult[j]
+    }
+    return y
+}
+
+// Solve a bounded knapsack problem by any solver for the 0/1 knapsack
+// problem. The solver is applied to the binary split problem.
+func SolveBounded(kp BoundedKnapsackProblem, solve func(KnapsackProblem) ([]int,int)) ([]int,int,error) {
+    if err := noFixedItems(kp); err != nil {
+	return nil,0,err
+    }
+    sp := SplitBounded(kp)
+    x,z := solve(sp)
+    return sp.Expand(x),z,nil
+}
+
+// Solve a bounded knapsack problem by branch and bound (Horowitz and Sahni)
+// on the binary split problem.
+//
+// Precondition: Profit[i]/Weight[i] >= Profit[i-1]/Weight[i-1] for i=1,...,n-1
+//
+// We do not check the precondition here!
+// Use kp.CheckSortedItems() to check the precondition.
+func BoundedBranchAndBound(kp BoundedKnapsackProblem) ([]int,int,error) {
+    return SolveBounded(kp, BranchAndBoundHS)
+}
@@ -0,0 +1,110 @@
+package kp
+
+import (
+    "math/rand"
+    "testing"
+)
+
+// Optimal objective function value of a problem with integer decision
+// variables X[i] in [0,bound(i)] by complete enumeration.
+func enumerateMultiple(kp KnapsackProblem, bound func(i int) int) int {
+    var enum func(i int, c int) int
+
+    n := kp.N()
+    enum = func(i int, c int) int {	// best profit of the items i,...,n-1
+	if i == n {
+	    return 0
+	}
+	best := 0
+	for k:=0 ; k<=bound(i) && k*kp.Weight(i)<=c ; k++ {
+	    if z := k*kp.Profit(i) + enum(i+1, c-k*kp.Weight(i)); z > best {
+		best = z
+	    }
+	}
+	return best
+    }
+    return enum(0, kp.Capacity())
+}
+
+// Objective function value of x with integer decision variables X[i] in
+// [0,bound(i)], -1 if x is infeasible.
+func objectiveMultiple(kp KnapsackProblem, bound func(i int) int, x []int) int {
+    if len(x) != kp.N() {
+	return -1
+    }
+    z := 0
+    w := 0
+    for i,xi := range x {
+	if xi < 0 || xi > bound(i) {
+	    return -1
+	}
+	z += xi*kp.Profit(i)
+	w += xi*kp.Weight(i)
+    }
+    if w > kp.Capacity() {
+	return -1
+    }
+    return z
+}
+
+// The first items of the test problems with bounds 0,...,3.
+func boundedProblems(t *testing.T) []KnapsackData {
+    var kps []KnapsackData
+
+    r := rand.New(rand.NewSource(1))
+    for _,kp := range testProblems(t) {
+	if kp.Dim > 6 {
+	    kp.Dim = 6
+	    kp.P, kp.W = kp.P[:6], kp.W[:6]
+	}
+	kp.Bounds = make([]int, kp.Dim)
+	for i := range kp.Bounds {
+	    kp.Bounds[i] = r.Intn(4)
+	}
+	kps = append(kps, kp)
+    }
+    return kps
+}
+
+func TestBounded(t *testing.T) {
+    solvers := []struct {
+	name  string
+	solve func(BoundedKnapsackProblem) ([]int,int,error)
+    }{
+	{ "dp", BoundedDynProg },
+	{ "bab", BoundedBranchAndBound },
+	{ "split dp", func(kp BoundedKnapsackProblem) ([]int,int,error) { return SolveBounded(kp, DynProg) } },
+    }
+    for _,kp := range boundedProblems(t) {
+	if err := CheckBounds(&kp); err != nil {
+	    t.Fatal(err)
+	}
+	zopt := enumerateMultiple(kp, kp.Bound)
+	value := func(x []int) int { return objectiveMultiple(kp, kp.Bound, x) }
+	for _,s := range solvers {
+	    x,z,err := s.solve(kp)
+	    if err != nil {
+		t.Fatalf("%s, %s: %v", s.name, kp.Name, err)
+	    }
+	    checkSolution(t, s.name + ", " + kp.Name, value, x, z, zopt, true)
+	}
+    }
+}
+
+func TestCheckBounds(t *testing.T) {
+    tests := []struct {
+	bounds []int
+	ok     bool
+    }{
+	{ nil, true },
+	{ []int{0,1,5}, true },
+	{ []int{1,1}, false },
+	{ []int{1,-1,1}, false },
+    }
+    for _,test := range tests {
+	kp := KnapsackData{ Dim: 3, P: []int{3,2,1}, W: []int{1,1,1}, C: 2, Bounds: test.bounds }
+	if err := CheckBounds(&kp); (err == nil) != test.ok {
+	    t.Errorf("bounds %v: %v", test.bounds, err)
+	}
+    }
+}
@@ -0,0 +1,347 @@
+package kp
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+)
+
+// Optimality certificate of a solution of a knapsack problem.
+//
+// A certificate of kind "tree" is the search tree of a branch and bound run.
+// The shape of the tree is stored in preorder, one bit per node (8 nodes per
+// byte): 1 for a branched node, 0 for a leaf. The sons of a branched node for
+// item i are the subtrees for X[i]=1 and X[i]=0, in this order. Bounds holds
+// the upper bound of every leaf in preorder, -1 for infeasible leaves.
+//
+// A certificate of kind "valuefunction" is the value function of the dynamic
+// programming: Values[i] are the breakpoints of v[i][s] (the maximal profit of
+// the items i,...,n-1 for the rest capacity s) for i=0,...,n.
+//
+// For a problem with fixed items the certificate refers to the reduced problem
+// (see Reduce()) and Fix holds the fix markers of the problem.
+//
+// CheckCertificate() verifies a certificate without solving the problem.
+type Certificate struct {
+    Kind   string         `json:"kind"`		// "tree" or "valuefunction"
+    Tree   []byte         `json:"tree,omitempty"`	// shape of the search tree, one bit per node
+    Bounds []int          `json:"bounds,omitempty"`	// upper bound of each leaf
+    Values [][]Breakpoint `json:"values,omitempty"`	// value function of the dynamic programming
+    Fix    []int          `json:"fix,omitempty"`	// fix markers of the problem, nil: no fixed items
+}
+
+// Records the leaves of a branch and bound run for a certificate.
+// A nil recorder records nothing.
+type treeRecorder struct {
+    cert   *Certificate
+    leaves []*stateT
+}
+
+func newTreeRecorder(opt BabOptions) (*treeRecorder,error) {
+    if opt.Certificate == nil {
+	return nil,nil
+    }
+    if opt.Resume {			// the tree before the checkpoint is unknown
+	return nil, errors.New("certificates are not available for resumed runs")
+    }
+    if opt.ExactFill {
+	return nil, errors.New("certificates are not available for exact fill")
+    }
+    return &treeRecorder{ cert: opt.Certificate }, nil
+}
+
+// Record a leaf (pruned state or goal state) of the search tree.
+func (t *treeRecorder) leaf(state *stateT) {
+    if t != nil {
+	t.leaves = append(t.leaves, state)
+    }
+}
+
+// Build the certificate from the recorded leaves. Every state on the path
+// from a leaf to the root is a branched node. A missing son for X[i]=1 is
+// an infeasible leaf.
+func (t *treeRecorder) store() {
+    var (
+	root *stateT
+	emit func(state *stateT)
+    )
+
+    if t == nil {
+	return
+    }
+    sons := make(map[*stateT]*[2]*stateT)
+    for _,leaf := range t.leaves {
+	for s:=leaf ; s.father!=nil ; s=s.father {
+	    f := sons[s.father]
+	    if f == nil {
+		f = new([2]*stateT)
+		sons[s.father] = f
+	    }
+	    if f[s.decision] == s {		// the rest of the path is known
+		break
+	    }
+	    f[s.decision] = s
+	}
+    }
+    if len(t.leaves) > 0 {
+	root = t.leaves[0]
+	for root.father != nil {
+	    root = root.father
+	}
+    }
+
+    *t.cert = Certificate{ Kind: "tree" }
+    nodes := 0
+    node := func(branched bool, bound int) {
+	if nodes % 8 == 0 {
+	    t.cert.Tree = append(t.cert.Tree, 0)
+	}
+	if branched {
+	    t.cert.Tree[nodes/8] |= 1 << uint(nodes%8)
+	} else {
+	    t.cert.Bounds = append(t.cert.Bounds, bound)
+	}
+	nodes++
+    }
+    emit = func(state *stateT) {
+	if state == nil {		// decision = 1 is infeasible
+	    node(false, -1)
+	    return
+	}
+	f := sons[state]
+	if f == nil {
+	    node(false, state.phi)
+	    return
+	}
+	node(true, 0)
+	emit(f[1])
+	emit(f[0])
+    }
+    if root != nil {
+	emit(root)
+    }
+}
+
+// Solve a knapsack problem with dynamic programming like DynProg() and
+// return a certificate of kind "valuefunction" for the solution.
+// The certificate needs O(n*Capacity) memory in the worst case, but usually
+// the value function has far less breakpoints than capacities.
+// An error is returned if the items fixed to 1 don't fit (see FixedItemsProblem).
+func DynProgCertificate(kp KnapsackProblem) ([]int,int,Certificate,error) {
+    if r, err := fixedReduction(kp); err != nil {	// fixed items don't fit
+	return nil,0,Certificate{},err
+    } else if r != nil {			// fixed items: certificate of the reduced problem
+	x,z,cert,_ := DynProgCertificate(r.Problem())	// no fixed items, no error
+	x,z = r.Expand(x,z)
+	cert.Fix = r.certFix()
+	return x,z,cert,nil
+    }
+
+    n := kp.N()
+    s := kp.Capacity()
+    v := valueTable(kp)
+    cert := Certificate{ Kind: "valuefunction", Values: make([][]Breakpoint, n+1) }
+    for i:=0 ; i<=n ; i++ {
+	cert.Values[i] = CapacityBreakpoints(v[i])
+    }
+
+    x := make([]int,n)			// forward computation as in DynProg():
+    for i:=0 ; i<n ; i++ {		// X[i] = 1 if it improves the value function
+	if v[i][s] != v[i+1][s] {
+	    x[i] = 1
+	    s -= kp.Weight(i)
+	}
+    }
+    return x, v[0][kp.Capacity()], cert, nil
+}
+
+// Verify that x is an optimal solution of a knapsack problem by a certificate.
+// The result is the objective function value of x.
+//
+// The check doesn't trust the solver: for a tree certificate every leaf
+// must be infeasible or its upper bound (recomputed in integer arithmetic)
+// must not exceed z. For a value function certificate the rows must satisfy
+// the inequalities of the dynamic programming recursion (so they are upper
+// bounds of the true value function) and v[0][Capacity] must not exceed z.
+//
+// If the problem has fixed items (see FixedItemsProblem), the certificate
+// must have the same fix markers, x must respect them and the certificate is
+// checked for the reduced problem.
+//
+// Precondition for tree certificates:
+// Profit[i]/Weight[i] >= Profit[i-1]/Weight[i-1] for i=1,...,n-1
+// Here we check the precondition exactly.
+func CheckCertificate(kp KnapsackProblem, x []int, cert *Certificate) (int,error) {
+    if len(x) != kp.N() {
+	return 0, errors.New("solution and problem have different sizes")
+    }
+    r, err := fixedReduction(kp)
+    if err != nil {
+	return 0,err
+    }
+    if !r.sameFix(cert.Fix) {
+	return 0, errors.New("invalid certificate: fix markers differ from those of the problem")
+    }
+    if r == nil {
+	return checkCertificate(kp, x, cert)
+    }
+    if err = r.checkSolution(x); err != nil {
+	return 0,err
+    }
+    z, err := checkCertificate(r.Problem(), r.Restrict(x), cert)
+    if err != nil {
+	return 0,err
+    }
+    return z + r.psum, nil
+}
+
+// Check a certificate for a problem without fixed items.
+func checkCertificate(kp KnapsackProblem, x []int, cert *Certificate) (int,error) {
+    n := kp.N()
+    z := 0
+    w := 0
+    for i:=0 ; i<n ; i++ {
+	if x[i] != 0 && x[i] != 1 {
+	    return 0, errors.New("solution is not a binary vector")
+	}
+	z += x[i]*kp.Profit(i)
+	w += x[i]*kp.Weight(i)
+    }
+    if w > kp.Capacity() {
+	return 0, errors.New("solution is infeasible")
+    }
+
+    var err error
+    switch cert.Kind {
+    case "tree":
+	err = checkTree(kp, z, cert)
+    case "valuefunction":
+	err = checkValueFunction(kp, z, cert)
+    default:
+	err = fmt.Errorf("unknown certificate kind: %s", cert.Kind)
+    }
+    if err != nil {
+	return 0,err
+    }
+    return z,nil
+}
+
+func checkTree(kp KnapsackProblem, z int, cert *Certificate) error {
+    var walk func(i int, psum int, wsum int) error
+
+    n := kp.N()
+    c := kp.Capacity()
+    for i:=1 ; i<n ; i++ {		// cross multiplication avoids rounding errors
+	if int64(kp.Profit(i))*int64(kp.Weight(i-1)) > int64(kp.Profit(i-1))*int64(kp.Weight(i)) {
+	    return errors.New("wrong input: items are not sorted according to decreasing profit/weight")
+	}
+    }
+
+    nodes := 0
+    leaves := 0
+    walk = func(i int, psum int, wsum int) error {	// check the subtree of the next node
+	if nodes >= 8*len(cert.Tree) {
+	    return errors.New("invalid certificate: tree is incomplete")
+	}
+	branched := cert.Tree[nodes/8] & (1 << uint(nodes%8)) != 0
+	nodes++
+	if branched {
+	    if i == n {
+		return errors.New("invalid certificate: branching after the last item")
+	    }
+	    err := walk(i+1, psum+kp.Profit(i), wsum+kp.Weight(i))	// X[i] = 1
+	    if err != nil {
+		return err
+	    }
+	    return walk(i+1, psum, wsum)				// X[i] = 0
+	}
+
+	if leaves >= len(cert.Bounds) {
+	    return errors.New("invalid certificate: bounds are missing")
+	}
+	bound := cert.Bounds[leaves]
+	leaves++
+	if wsum > c {			// infeasible leaf
+	    return nil
+	}
+	ub := psum + lpBound(kp, c-wsum, i)
+	if bound < ub {
+	    return fmt.Errorf("invalid certificate: bound of leaf %v is %v, but the LP bound is %v", leaves-1, bound, ub)
+	}
+	if bound > z {
+	    return fmt.Errorf("invalid certificate: bound %v of leaf %v exceeds z = %v", bound, leaves-1, z)
+	}
+	return nil
+    }
+
+    err := walk(0, 0, 0)
+    if err != nil {
+	return err
+    }
+    if leaves != len(cert.Bounds) || (nodes+7)/8 != len(cert.Tree) {
+	return errors.New("invalid certificate: tree is too large")
+    }
+    return nil
+}
+
+// Upper bound of the LP relaxation for the items istart,...,n-1 and the
+// rest capacity c like uBound1P(), but computed in integer arithmetic.
+func lpBound(kp KnapsackProblem, c int, istart int) int {
+    n := kp.N()
+    ub := 0
+    i := istart
+    for ; i<n && kp.Weight(i)<=c ; i++ {
+	ub += kp.Profit(i)
+	c -= kp.Weight(i)
+    }
+    if i<n {
+	ub += int(int64(kp.Profit(i)) * int64(c) / int64(kp.Weight(i)))
+    }
+    return ub
+}
+
+func checkValueFunction(kp KnapsackProblem, z int, cert *Certificate) error {
+    n := kp.N()
+    c := kp.Capacity()
+    if len(cert.Values) != n+1 {
+	return fmt.Errorf("invalid certificate: %v rows of the value function instead of %v", len(cert.Values), n+1)
+    }
+    for i,row := range cert.Values {	// step functions starting at capacity 0
+	if len(row) == 0 || row[0].Capacity != 0 {
+	    return fmt.Errorf("invalid certificate: row %v doesn't start at capacity 0", i)
+	}
+	for k:=1 ; k<len(row) ; k++ {
+	    if row[k].Capacity <= row[k-1].Capacity || row[k].Capacity > c || row[k].Z < row[k-1].Z {
+		return fmt.Errorf("invalid certificate: breakpoints of row %v are not increasing", i)
+	    }
+	}
+    }
+    if cert.Values[n][0].Z < 0 {		// v[n][s] >= 0
+	return errors.New("invalid certificate: last row is negative")
+    }
+
+    // v[i][s] >= v[i+1][s] and v[i][s] >= Profit[i] + v[i+1][s-Weight[i]].
+    // v[i+1] is constant between its breakpoints and v[i] is monotone,
+    // so it suffices to check the inequalities at the breakpoints of v[i+1].
+    for i:=n-1 ; i>=0 ; i-- {
+	for _,bp := range cert.Values[i+1] {
+	    if valueAt(cert.Values[i], bp.Capacity) < bp.Z {
+		return fmt.Errorf("invalid certificate: row %v violates X[%v]=0 at capacity %v", i, i, bp.Capacity)
+	    }
+	    s := bp.Capacity + kp.Weight(i)
+	    if s <= c && valueAt(cert.Values[i], s) < kp.Profit(i) + bp.Z {
+		return fmt.Errorf("invalid certificate: row %v violates X[%v]=1 at capacity %v", i, i, s)
+	    }
+	}
+    }
+    if v := valueAt(cert.Values[0], c); v > z {
+	return fmt.Errorf("invalid certificate: optimal value %v exceeds z = %v", v, z)
+    }
+    return nil
+}
+
+// Value of a step function given by its breakpoints at capacity s.
+func valueAt(row []Breakpoint, s int) int {
+    k := sort.Search(len(row), func(k int) bool { return row[k].Capacity > s })
+    return row[k-1].Z
+}
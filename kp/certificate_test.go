@@ -0,0 +1,100 @@
+package kp
+
+import (
+    "testing"
+)
+
+// Certificates of the exact solvers for a problem.
+func certificates(t *testing.T, kp KnapsackProblem) map[string]Certificate {
+    certs := make(map[string]Certificate)
+    for _,s := range babSolvers {
+	var cert Certificate
+
+	if _,_,err := s.bab(kp, BabOptions{ Certificate: &cert }); err != nil {
+	    t.Fatal(err)
+	}
+	certs[s.name] = cert
+    }
+    _,_,certs["dp"],_ = DynProgCertificate(kp)
+    return certs
+}
+
+func TestCertificate(t *testing.T) {
+    for _,kp := range testProblems(t) {
+	xopt,zopt := DynProg(kp)
+	xg,zg := Greedy(kp)
+	for name,cert := range certificates(t, kp) {
+	    z,err := CheckCertificate(kp, xopt, &cert)
+	    if err != nil || z != zopt {
+		t.Errorf("%s, %s: z = %v, error %v", name, kp.Name, z, err)
+	    }
+	    if _,err = CheckCertificate(kp, xg, &cert); (err == nil) != (zg == zopt) {
+		t.Errorf("%s, %s: greedy solution with z = %v, error %v", name, kp.Name, zg, err)
+	    }
+	}
+    }
+}
+
+func TestCertificateTampered(t *testing.T) {
+    kp := testProblems(t)[len(testProblems(t))-1]
+    x,_ := DynProg(kp)
+    tests := []struct {
+	name   string
+	cert   string
+	tamper func(cert *Certificate)
+    }{
+	{ "unknown kind", "dp", func(cert *Certificate) { cert.Kind = "proof" } },
+	{ "missing row", "dp", func(cert *Certificate) { cert.Values = cert.Values[1:] } },
+	{ "lower value", "dp", func(cert *Certificate) {
+	    row := cert.Values[0]
+	    row[len(row)-1].Z--
+	} },
+	{ "bounds too low", "hs", func(cert *Certificate) {
+	    for k := range cert.Bounds {
+		cert.Bounds[k] = 0
+	    }
+	} },
+	{ "missing bound", "bab", func(cert *Certificate) { cert.Bounds = cert.Bounds[1:] } },
+	{ "incomplete tree", "hs", func(cert *Certificate) { cert.Tree = cert.Tree[:len(cert.Tree)-1] } },
+	{ "fix markers", "dp", func(cert *Certificate) { cert.Fix = make([]int, kp.N()); cert.Fix[0] = FixOut } },
+    }
+    for _,test := range tests {
+	cert := certificates(t, kp)[test.cert]
+	test.tamper(&cert)
+	if _,err := CheckCertificate(kp, x, &cert); err == nil {
+	    t.Errorf("%s: no error", test.name)
+	}
+    }
+}
+
+func TestCertificateFixedItems(t *testing.T) {
+    kp := testProblems(t)[len(testProblems(t))-1]
+    kp.Fix = make([]int, kp.N())
+    kp.Fix[0], kp.Fix[1], kp.Fix[kp.N()-1] = FixOut, FixIn, FixIn
+    zopt,_ := enumerateFixed(kp)
+    x,_ := DynProg(kp)
+    xc,zc,_,err := DynProgCertificate(kp)	// the solution respects the fix markers, too
+    if err != nil {
+	t.Fatal(err)
+    }
+    checkSolution(t, "certificate", func(x []int) int { return objectiveFixed(kp, x) }, xc, zc, zopt, true)
+    for name,cert := range certificates(t, kp) {
+	if len(cert.Fix) != kp.N() {
+	    t.Errorf("%s: fix markers %v", name, cert.Fix)
+	}
+	if z,err := CheckCertificate(kp, x, &cert); err != nil || z != zopt {
+	    t.Errorf("%s: z = %v, error %v", name, z, err)
+	}
+
+	free := kp				// the certificate doesn't prove
+	free.Fix = nil				// the optimality without fixed items
+	if _,err := CheckCertificate(free, x, &cert); err == nil {
+	    t.Errorf("%s: no error without fixed items", name)
+	}
+	y := append([]int(nil), x...)		// x violates the fix markers
+	y[kp.N()-1] = 0
+	if _,err := CheckCertificate(kp, y, &cert); err == nil {
+	    t.Errorf("%s: no error for a solution violating the fix markers", name)
+	}
+    }
+}
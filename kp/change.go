@@ -0,0 +1,144 @@
+package kp
+
+import (
+    "errors"
+)
+
+// Change-making problem: pay an amount with the minimal number of coins of
+// given denominations. The number of coins of a denomination may be limited
+// (bounded) or unlimited (unbounded).
+type ChangeMakingProblem interface {
+    N()                 int		// number of denominations, 0,...,n-1
+    Denomination(i int) int		// value of a coin of denomination i
+    Available(i int)    int		// number of coins of denomination i, -1: unlimited
+    Amount()            int		// amount to pay
+}
+
+// Change-making problem data
+type ChangeData struct {
+    Name    string `json:"name,omitempty"`	// problem name, optional
+    Comment string `json:"comment,omitempty"`	// comment, optional
+    Type    string `json:"type"`		// problem type, unused at the moment
+    Dim     int    `json:"dimension"`		// number of denominations
+    D       []int  `json:"denominations"`	// value of the coins
+    Avail   []int  `json:"available,omitempty"`	// number of available coins of each
+						// denomination, -1 or omitted: unlimited
+    A       int    `json:"amount"`		// amount to pay
+    X       []int  `json:"x,omitempty"`	// number of coins of each denomination
+    Z       int    `json:"z,omitempty"`	// total number of coins
+}
+
+func (cm ChangeData) N() int {
+    return cm.Dim
+}
+
+func (cm ChangeData) Denomination(i int) int {
+    return cm.D[i]
+}
+
+func (cm ChangeData) Available(i int) int {
+    if cm.Avail == nil {
+	return -1
+    }
+    return cm.Avail[i]
+}
+
+func (cm ChangeData) Amount() int {
+    return cm.A
+}
+
+var errNoChange = errors.New("infeasible: the amount can't be paid with the available coins")
+
+// Check the sizes, denominations and availabilities of a change-making problem.
+func CheckChangeData(cm *ChangeData) error {
+    if len(cm.D) != cm.Dim {
+	return errors.New("wrong input: denominations and problem have different sizes")
+    }
+    if cm.Avail != nil && len(cm.Avail) != cm.Dim {
+	return errors.New("wrong input: available coins and problem have different sizes")
+    }
+    if cm.A < 0 {
+	return errors.New("wrong input: negative amount")
+    }
+    for i,d := range cm.D {
+	if d <= 0 {
+	    return errors.New("wrong input: denominations must be positive")
+	}
+	if cm.Avail != nil && cm.Avail[i] < -1 {
+	    return errors.New("wrong input: negative number of available coins")
+	}
+    }
+    return nil
+}
+
+// Solve a change-making problem with dynamic programming.
+//
+// Like BoundedDynProg(), but we minimize and only exact amounts are feasible:
+// v[i][s] is the minimum of k + v[i+1][s-k*Denomination[i]] for
+// k=0,...,Available[i] (unlimited coins: k <= s/Denomination[i]) and
+// v[n][0] = 0. For a fixed residue r = s mod Denomination[i] this is the
+// minimum over a sliding window of v[i+1][r+t*Denomination[i]] - t, which we
+// maintain in a monotone queue. So we need O(n*Amount) time.
+// The solution x contains the number of coins of each denomination, the
+// second result is the total number of coins.
+// An error is returned if the amount can't be paid.
+func ChangeMaking(cm ChangeMakingProblem) ([]int,int,error) {
+    const none = -1			// amount can't be paid
+
+    n := cm.N()
+    a := cm.Amount()
+
+    policy := makePolicyTable(n, a)	// policy[i][s] stores the optimal number of
+					// coins of denomination i for amount s
+    vv := make([]int, a+1)		// value function for denomination i+1
+    for s:=1 ; s<=a ; s++ {
+	vv[s] = none
+    }
+    q := make([]int, a+1)		// monotone queue of multiples t
+
+    // Backward computation
+    for i:=n-1 ; i>=0 ; i-- {
+	d, b := cm.Denomination(i), cm.Available(i)
+	if b < 0 {			// unlimited coins
+	    b = a/d
+	}
+	v := make([]int, a+1)		// value function for denomination i
+	for r:=0 ; r<d && r<=a ; r++ {	// residue classes s = r + j*d
+	    head, tail := 0, 0
+	    for j:=0 ; r+j*d<=a ; j++ {
+		if vv[r+j*d] != none {	// only payable amounts enter the queue
+		    g := vv[r+j*d] - j	// value of t = j
+		    for tail > head && vv[r+q[tail-1]*d] - q[tail-1] >= g {
+			tail--		// on ties we keep the larger t (less coins)
+		    }
+		    q[tail] = j
+		    tail++
+		}
+		for head < tail && q[head] < j-b {	// at most b coins
+		    head++
+		}
+		if head == tail {
+		    v[r+j*d] = none
+		    continue
+		}
+		t := q[head]
+		v[r+j*d] = vv[r+t*d] + (j-t)
+		policy[i][r+j*d] = j-t
+	    }
+	}
+	vv = v
+    }
+    if vv[a] == none {
+	return nil,0,errNoChange
+    }
+
+    // Forward computation
+    x := make([]int,n)
+    s := a
+    for i:=0 ; i<n ; i++ {
+	x[i] = policy[i][s]
+	s -= x[i]*cm.Denomination(i)
+    }
+
+    return x,vv[a],nil
+}
@@ -0,0 +1,94 @@
+package kp
+
+import (
+    "testing"
+)
+
+// Minimal number of coins by complete enumeration, -1 if the amount can't
+// be paid.
+func enumerateChange(cm ChangeData) int {
+    var enum func(i int, a int) int
+
+    enum = func(i int, a int) int {	// coins of the denominations i,...,n-1
+	if a == 0 {
+	    return 0
+	}
+	if i == cm.N() {
+	    return -1
+	}
+	best := -1
+	for k:=0 ; k*cm.D[i]<=a && (cm.Available(i) < 0 || k<=cm.Available(i)) ; k++ {
+	    if z := enum(i+1, a-k*cm.D[i]); z >= 0 && (best < 0 || k+z < best) {
+		best = k+z
+	    }
+	}
+	return best
+    }
+    return enum(0, cm.A)
+}
+
+func TestChangeMaking(t *testing.T) {
+    tests := []ChangeData{
+	{ Name: "greedy is optimal", Dim: 3, D: []int{1,2,5}, A: 13 },
+	{ Name: "greedy is not optimal", Dim: 3, D: []int{1,3,4}, A: 6 },
+	{ Name: "no coin of 1", Dim: 2, D: []int{4,7}, A: 13 },
+	{ Name: "can't be paid", Dim: 2, D: []int{4,6}, A: 13 },
+	{ Name: "limited coins", Dim: 3, D: []int{1,5,10}, Avail: []int{3,1,-1}, A: 28 },
+	{ Name: "limited coins, can't be paid", Dim: 2, D: []int{2,5}, Avail: []int{1,2}, A: 9 },
+	{ Name: "zero amount", Dim: 1, D: []int{3}, A: 0 },
+	{ Name: "no denominations", Dim: 0, D: []int{}, A: 3 },
+    }
+    for a:=1 ; a<=40 ; a++ {
+	tests = append(tests, ChangeData{ Name: "bounded", Dim: 4, D: []int{2,3,7,9}, Avail: []int{2,3,1,-1}, A: a })
+    }
+
+    for _,cm := range tests {
+	if err := CheckChangeData(&cm); err != nil {
+	    t.Fatal(err)
+	}
+	zopt := enumerateChange(cm)
+	x,z,err := ChangeMaking(cm)
+	if zopt < 0 {
+	    if err == nil {
+		t.Errorf("%s, amount %v: no error, but the amount can't be paid", cm.Name, cm.A)
+	    }
+	    continue
+	}
+	if err != nil {
+	    t.Errorf("%s, amount %v: %v", cm.Name, cm.A, err)
+	    continue
+	}
+	sum := 0
+	coins := 0
+	for i,xi := range x {
+	    if xi < 0 || (cm.Available(i) >= 0 && xi > cm.Available(i)) {
+		t.Errorf("%s, amount %v: %v coins of %v", cm.Name, cm.A, xi, cm.D[i])
+	    }
+	    sum += xi*cm.D[i]
+	    coins += xi
+	}
+	if sum != cm.A || coins != z || z != zopt {
+	    t.Errorf("%s, amount %v: x = %v, z = %v, but the optimum is %v", cm.Name, cm.A, x, z, zopt)
+	}
+    }
+}
+
+func TestCheckChangeData(t *testing.T) {
+    tests := []struct {
+	name string
+	cm   ChangeData
+	ok   bool
+    }{
+	{ "valid", ChangeData{ Dim: 2, D: []int{1,2}, Avail: []int{-1,3}, A: 5 }, true },
+	{ "size", ChangeData{ Dim: 2, D: []int{1}, A: 5 }, false },
+	{ "available", ChangeData{ Dim: 2, D: []int{1,2}, Avail: []int{1}, A: 5 }, false },
+	{ "negative amount", ChangeData{ Dim: 1, D: []int{1}, A: -5 }, false },
+	{ "zero denomination", ChangeData{ Dim: 1, D: []int{0}, A: 5 }, false },
+	{ "negative availability", ChangeData{ Dim: 1, D: []int{1}, Avail: []int{-2}, A: 5 }, false },
+    }
+    for _,test := range tests {
+	if err := CheckChangeData(&test.cm); (err == nil) != test.ok {
+	    t.Errorf("%s: %v", test.name, err)
+	}
+    }
+}
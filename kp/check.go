@@ -2,6 +2,7 @@ package kp
 
 import (
     "errors"
+    "fmt"
 )
 
 func CheckSortedItems(kp KnapsackProblem) error {
@@ -14,3 +15,29 @@ func CheckSortedItems(kp KnapsackProblem) error {
     }
     return nil
 }
+
+// The weights of the unbounded knapsack problem must be positive, otherwise
+// an item with weight 0 could be selected infinitely often.
+func CheckPositiveWeights(kp KnapsackProblem) error {
+    n := kp.N()
+    for i:=0 ; i<n ; i++ {
+	if kp.Weight(i) <= 0 {
+	    return errors.New("wrong input: weights must be positive")
+	}
+    }
+    return nil
+}
+
+// Check that x is a binary vector for the problem size n. what names x in
+// the error messages, e.g. "incumbent".
+func checkBinary(x []int, n int, what string) error {
+    if len(x) != n {
+	return fmt.Errorf("%s and problem have different sizes", what)
+    }
+    for _,xi := range x {
+	if xi != 0 && xi != 1 {
+	    return fmt.Errorf("%s is not a binary vector", what)
+	}
+    }
+    return nil
+}
@@ -0,0 +1,230 @@
+package kp
+
+import (
+    "encoding/json"
+    "fmt"
+    "io/ioutil"
+    "os"
+    "time"
+)
+
+// Checkpoint of a branch and bound run as it is written to a file.
+//
+// The states of the agenda share most of their father chains. Hence we store
+// every state of the search tree only once: a state is given by the index of
+// its father state and its decision, fathers always come first.
+// The decisions are packed into a bit vector (8 decisions per byte).
+// Profit sums, residual capacities and upper bounds are recomputed on resume.
+type checkpointData struct {
+    Algorithm string `json:"algorithm"`		// "bab" or "hs"
+    N         int    `json:"n"`			// number of items
+    Capacity  int    `json:"capacity"`		// capacity of the knapsack
+    PSum      int    `json:"psum"`		// sum of all profits and
+    WSum      int    `json:"wsum"`		// weights to identify the instance
+    Father    []int  `json:"father"`		// father index of each state, -1 for the root
+    Decision  []byte `json:"decision"`		// decision of each state, one bit per state
+    Agenda    []int  `json:"agenda"`		// indices of the agenda states in agenda order
+    Best      int    `json:"best"`		// index of the actual best state, -1 if none
+    Pmax      int    `json:"pmax"`		// actual best solution value
+    Incumbent []int  `json:"incumbent,omitempty"`	// initial incumbent, omitted if there is none
+    Z0        int    `json:"z0"`			// value of the initial incumbent, -1 if there is none
+    ExactFill bool   `json:"exactfill,omitempty"`	// run with BabOptions.ExactFill
+    Nodes     int64  `json:"nodes"`		// number of generated states so far
+}
+
+// Agenda, actual best solution and initial incumbent of a branch and bound
+// run, as it is written to or restored from a checkpoint.
+// The incumbent is part of the checkpoint, because the agenda has been pruned
+// against it: on resume, it is the fallback if no better solution is found.
+type checkpointT struct {
+    agenda    []*stateT
+    best      *stateT
+    pmax      int
+    incumbent []int		// initial incumbent, nil if there is none
+    z0        int		// its value, -1 if there is none
+    exactFill bool
+    nodes     int64
+}
+
+// Writes checkpoints periodically.
+// To keep the overhead small, the clock is only read every 1024 calls of due().
+type checkpointer struct {
+    file     string
+    interval time.Duration
+    next     time.Time
+    calls    int
+}
+
+func newCheckpointer(opt BabOptions) *checkpointer {
+    if opt.CheckpointFile == "" {
+	return nil
+    }
+    interval := opt.CheckpointInterval
+    if interval <= 0 {
+	interval = time.Minute
+    }
+    return &checkpointer{ file: opt.CheckpointFile, interval: interval,
+			  next: time.Now().Add(interval) }
+}
+
+// Is it time for a new checkpoint? A nil checkpointer is never due.
+func (cp *checkpointer) due() bool {
+    if cp == nil {
+	return false
+    }
+    cp.calls++
+    if cp.calls % 1024 != 0 {
+	return false
+    }
+    return time.Now().After(cp.next)
+}
+
+// Write the agenda and the actual best solution to the checkpoint file.
+// We write to a temporary file first and rename it afterwards, so a crash
+// while writing never destroys the last checkpoint.
+func (cp *checkpointer) write(kp KnapsackProblem, alg string, run *checkpointT) error {
+    ck := encodeCheckpoint(kp, alg, run)
+    b, err := json.Marshal(ck)
+    if err != nil {
+	return err
+    }
+    tmp := cp.file + ".tmp"
+    err = ioutil.WriteFile(tmp, b, 0644)
+    if err != nil {
+	return err
+    }
+    err = os.Rename(tmp, cp.file)
+    if err != nil {
+	return err
+    }
+    cp.next = time.Now().Add(cp.interval)
+    return nil
+}
+
+func encodeCheckpoint(kp KnapsackProblem, alg string, run *checkpointT) *checkpointData {
+    ck := &checkpointData{ Algorithm: alg, N: kp.N(), Capacity: kp.Capacity(), Pmax: run.pmax,
+			   Incumbent: run.incumbent, Z0: run.z0, ExactFill: run.exactFill, Nodes: run.nodes }
+    ck.PSum, ck.WSum = instanceSums(kp)
+
+    index := make(map[*stateT]int)
+    var chain []*stateT
+    add := func(state *stateT) int {	// add a state and its fathers, returns its index
+	chain = chain[:0]
+	for s:=state ; s!=nil ; s=s.father {	// collect the states not stored yet
+	    if _,ok := index[s]; ok {
+		break
+	    }
+	    chain = append(chain, s)
+	}
+	for j:=len(chain)-1 ; j>=0 ; j-- {	// and store them, fathers first
+	    s := chain[j]
+	    i := len(ck.Father)
+	    index[s] = i
+	    if s.father == nil {
+		ck.Father = append(ck.Father, -1)
+	    } else {
+		ck.Father = append(ck.Father, index[s.father])
+	    }
+	    if i % 8 == 0 {
+		ck.Decision = append(ck.Decision, 0)
+	    }
+	    if s.decision == 1 {
+		ck.Decision[i/8] |= 1 << uint(i%8)
+	    }
+	}
+	return index[state]
+    }
+
+    ck.Agenda = make([]int, len(run.agenda))
+    for j,s := range run.agenda {
+	ck.Agenda[j] = add(s)
+    }
+    ck.Best = -1
+    if run.best != nil {
+	ck.Best = add(run.best)
+    }
+
+    return ck
+}
+
+// Read a checkpoint written by algorithm alg for the knapsack problem kp
+// and rebuild the states of the agenda. The run must have the same exact fill
+// mode, the incumbent of the checkpoint replaces the one of the options.
+func readCheckpoint(kp KnapsackProblem, file string, alg string, exactFill bool) (*checkpointT, error) {
+    var ck checkpointData
+
+    b, err := ioutil.ReadFile(file)
+    if err != nil {
+	return nil, err
+    }
+    err = json.Unmarshal(b, &ck)
+    if err != nil {
+	return nil, err
+    }
+
+    if ck.Algorithm != alg {
+	return nil, fmt.Errorf("checkpoint %s was written by algorithm %s, not %s", file, ck.Algorithm, alg)
+    }
+    psum, wsum := instanceSums(kp)
+    if ck.N != kp.N() || ck.Capacity != kp.Capacity() || ck.PSum != psum || ck.WSum != wsum {
+	return nil, fmt.Errorf("checkpoint %s belongs to another problem instance", file)
+    }
+    if ck.ExactFill != exactFill {
+	return nil, fmt.Errorf("checkpoint %s was written with exact fill %v, not %v", file, ck.ExactFill, exactFill)
+    }
+    if ck.Incumbent == nil && (!exactFill || ck.Z0 != -1) {
+	return nil, fmt.Errorf("corrupted checkpoint %s: no incumbent", file)
+    }
+    if ck.Incumbent != nil {
+	if z, err := CheckIncumbent(kp, ck.Incumbent, exactFill); err != nil || z != ck.Z0 {
+	    return nil, fmt.Errorf("corrupted checkpoint %s: invalid incumbent", file)
+	}
+    }
+    if len(ck.Decision)*8 < len(ck.Father) {
+	return nil, fmt.Errorf("corrupted checkpoint %s", file)
+    }
+
+    states := make([]*stateT, len(ck.Father))
+    for i,f := range ck.Father {	// fathers come first, so we can rebuild
+	if f < 0 {			// the states in the stored order
+	    states[i] = initialState(kp)
+	    continue
+	}
+	if f >= i || states[f].nitems >= kp.N() {
+	    return nil, fmt.Errorf("corrupted checkpoint %s", file)
+	}
+	if ck.Decision[i/8] & (1 << uint(i%8)) != 0 {
+	    states[i] = successor1(kp, states[f])
+	} else {
+	    states[i] = successor0(kp, states[f])
+	}
+    }
+
+    cks := &checkpointT{ agenda: make([]*stateT, len(ck.Agenda)), pmax: ck.Pmax,
+			 incumbent: ck.Incumbent, z0: ck.Z0, exactFill: ck.ExactFill, nodes: ck.Nodes }
+    for j,i := range ck.Agenda {
+	if i < 0 || i >= len(states) {
+	    return nil, fmt.Errorf("corrupted checkpoint %s", file)
+	}
+	cks.agenda[j] = states[i]
+    }
+    if ck.Best >= len(states) {
+	return nil, fmt.Errorf("corrupted checkpoint %s", file)
+    }
+    if ck.Best >= 0 {
+	cks.best = states[ck.Best]
+    }
+
+    return cks, nil
+}
+
+// Sums of all profits and weights, used to identify a problem instance.
+func instanceSums(kp KnapsackProblem) (int,int) {
+    psum := 0
+    wsum := 0
+    for i:=0 ; i<kp.N() ; i++ {
+	psum += kp.Profit(i)
+	wsum += kp.Weight(i)
+    }
+    return psum,wsum
+}
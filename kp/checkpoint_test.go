@@ -0,0 +1,109 @@
+package kp
+
+import (
+    "path/filepath"
+    "testing"
+)
+
+// Partial runs of both algorithms with the incumbent x: expand the first
+// steps states and return the agenda, the actual best state and the incumbent.
+func partialRun(kp KnapsackProblem, alg string, steps int, x []int) *checkpointT {
+    z,_ := CheckIncumbent(kp, x, false)
+    run := &checkpointT{ agenda: []*stateT{ initialState(kp) }, pmax: z, incumbent: x, z0: z }
+    for k:=0 ; k<steps && len(run.agenda)>0 ; k++ {
+	var s1 *stateT
+
+	state := run.agenda[0]			// bab: best upper bound first
+	if alg == "hs" {
+	    state = run.agenda[len(run.agenda)-1]	// hs: depth first
+	}
+	if state.nitems == kp.N() {
+	    if alg == "bab" {
+		break
+	    }
+	    run.agenda = run.agenda[:len(run.agenda)-1]
+	    if state.psum > run.pmax {
+		run.pmax, run.best = state.psum, state
+	    }
+	    continue
+	}
+	if alg == "hs" {
+	    run.agenda = run.agenda[:len(run.agenda)-1]
+	    if state.phi <= run.pmax {		// pruned
+		continue
+	    }
+	}
+	if state.capacity >= kp.Weight(state.nitems) {
+	    s1 = successor1(kp, state)
+	}
+	s0 := successor0(kp, state)
+	if alg == "bab" {			// prune against the incumbent
+	    if s1 != nil && s1.phi <= run.pmax {
+		s1 = nil
+	    }
+	    if s0.phi <= run.pmax {
+		s0 = nil
+	    }
+	    run.agenda = pqUpdate(run.agenda, s1, s0)
+	    continue
+	}
+	run.agenda = append(run.agenda, s0)
+	if s1 != nil {
+	    run.agenda = append(run.agenda, s1)
+	}
+    }
+    return run
+}
+
+// Resume checkpoints written with the empty knapsack, the greedy and the
+// optimal solution as incumbent. The options of the resumed run don't matter,
+// the incumbent of the checkpoint is used.
+func TestCheckpointResume(t *testing.T) {
+    file := filepath.Join(t.TempDir(), "checkpoint")
+    for _,kp := range testProblems(t) {
+	xopt,_ := DynProg(kp)
+	xg,_ := Greedy(kp)
+	for _,s := range babSolvers {
+	    for _,inc := range [][]int{ make([]int, kp.N()), xg, xopt } {
+		for _,steps := range []int{ 0, 5, 20 } {
+		    cp := &checkpointer{ file: file }
+		    if err := cp.write(kp, s.name, partialRun(kp, s.name, steps, inc)); err != nil {
+			t.Fatal(err)
+		    }
+		    for _,opt := range []BabOptions{ {}, { NoWarmStart: true }, { Incumbent: xg } } {
+			opt.CheckpointFile, opt.Resume = file, true
+			x,z,err := s.bab(kp, opt)
+			if err != nil {
+			    t.Errorf("%s, %s: %v", s.name, kp.Name, err)
+			    continue
+			}
+			checkOptimal(t, s.name, kp, x, z)
+		    }
+		}
+	    }
+	}
+    }
+}
+
+func TestCheckpointMismatch(t *testing.T) {
+    kps := testProblems(t)
+    file := filepath.Join(t.TempDir(), "checkpoint")
+
+    if _,_,err := BranchAndBoundOpt(kps[0], BabOptions{ CheckpointFile: file, Resume: true }); err == nil {
+	t.Error("resume without checkpoint file: no error")
+    }
+
+    cp := &checkpointer{ file: file }
+    if err := cp.write(kps[1], "hs", partialRun(kps[1], "hs", 3, make([]int, kps[1].N()))); err != nil {
+	t.Fatal(err)
+    }
+    if _,_,err := BranchAndBoundHSOpt(kps[2], BabOptions{ CheckpointFile: file, Resume: true }); err == nil {
+	t.Error("checkpoint of another problem: no error")
+    }
+    if _,_,err := BranchAndBoundOpt(kps[1], BabOptions{ CheckpointFile: file, Resume: true }); err == nil {
+	t.Error("checkpoint of another algorithm: no error")
+    }
+    if _,_,err := BranchAndBoundHSOpt(kps[1], BabOptions{ CheckpointFile: file, Resume: true, ExactFill: true }); err == nil {
+	t.Error("checkpoint without exact fill: no error")
+    }
+}
@@ -0,0 +1,108 @@
+package kp
+
+import (
+    "math"
+)
+
+// A view on the items First(),...,First()+N()-1 of a knapsack problem with
+// a reduced capacity. CoreView implements KnapsackProblem without copying
+// the profit and weight values, item i of the view is item First()+i of the
+// underlying problem.
+type CoreView struct {
+    kp    KnapsackProblem	// underlying knapsack problem
+    first int			// first item of the view
+    n     int			// number of items of the view
+    c     int			// capacity
+}
+
+func (v CoreView) N() int {
+    return v.n
+}
+
+func (v CoreView) Capacity() int {
+    return v.c
+}
+
+func (v CoreView) Profit(i int) int {
+    return v.kp.Profit(v.first+i)
+}
+
+func (v CoreView) Weight(i int) int {
+    return v.kp.Weight(v.first+i)
+}
+
+// Index of the first item of the view in the underlying problem.
+func (v CoreView) First() int {
+    return v.first
+}
+
+// Core problem of a knapsack problem
+type Core struct {
+    View  CoreView	// the core sub-instance
+    Ones  []int		// items outside the core fixed to 1
+    Zeros []int		// items outside the core fixed to 0
+    PSum  int		// profit sum of the items fixed to 1
+}
+
+// Extract the core problem around the critical item s.
+//
+// The core consists of size consecutive items containing s. The items in front
+// of the core are fixed to 1, the items behind the core are fixed to 0 and the
+// capacity of the core is the residual capacity after packing the items fixed
+// to 1. If size <= 0, we use the heuristic core size 2*sqrt(n) (at least 10).
+//
+// For a well chosen core, an optimal solution of the core problem expanded
+// by Core.Solution() is an optimal solution of the whole problem. This is not
+// guaranteed, but usually the case for large instances.
+//
+// Precondition: Profit[i]/Weight[i] >= Profit[i-1]/Weight[i-1] for i=1,...,n-1
+//
+// We do not check the precondition here!
+// Use kp.CheckSortedItems() to check the precondition.
+func CoreProblem(kp KnapsackProblem, size int) Core {
+    n := kp.N()
+    if size <= 0 {				// heuristic core size
+	size = int(math.Ceil(2*math.Sqrt(float64(n))))
+	if size < 10 {
+	    size = 10
+	}
+    }
+    if size > n {
+	size = n
+    }
+
+    s := criticalItem(kp)
+    first := s - size/2				// core [first, first+size) around s
+    if first+size > n {
+	first = n - size
+    }
+    if first < 0 {
+	first = 0
+    }
+
+    core := Core{ View: CoreView{ kp: kp, first: first, n: size, c: kp.Capacity() } }
+    for i:=0 ; i<first ; i++ {			// items in front of the core
+	core.Ones = append(core.Ones, i)
+	core.PSum += kp.Profit(i)
+	core.View.c -= kp.Weight(i)
+    }
+    for i:=first+size ; i<n ; i++ {		// items behind the core
+	core.Zeros = append(core.Zeros, i)
+    }
+
+    return core
+}
+
+// Expand a solution x of the core problem with objective function value z
+// to a solution of the whole problem.
+func (core Core) Solution(x []int, z int) ([]int,int) {
+    n := core.View.kp.N()
+    y := make([]int,n)
+    for _,i := range core.Ones {
+	y[i] = 1
+    }
+    for i:=0 ; i<core.View.n ; i++ {
+	y[core.View.first+i] = x[i]
+    }
+    return y, z + core.PSum
+}
@@ -0,0 +1,32 @@
+package kp
+
+import (
+    "testing"
+)
+
+func TestCoreProblem(t *testing.T) {
+    for _,kp := range testProblems(t) {
+	n := kp.N()
+	zopt := enumerate(kp)
+	for _,size := range []int{ 1, 3, 5, n } {
+	    core := CoreProblem(kp, size)
+	    v := core.View
+	    if s := criticalItem(kp); s < n && (s < v.First() || s >= v.First()+v.N()) {
+		t.Errorf("%s, size %v: critical item %v is not in the core [%v,%v)", kp.Name, size, s, v.First(), v.First()+v.N())
+	    }
+	    if len(core.Ones) + v.N() + len(core.Zeros) != n {
+		t.Errorf("%s, size %v: core doesn't partition the items", kp.Name, size)
+	    }
+	    if v.Capacity() < 0 {
+		t.Errorf("%s, size %v: negative core capacity %v", kp.Name, size, v.Capacity())
+		continue
+	    }
+
+	    x,z := core.Solution(DynProg(v))
+	    checkFeasible(t, "core", kp, x, z)
+	    if z > zopt || (size == n && z != zopt) {
+		t.Errorf("%s, size %v: z = %v, but the optimum is %v", kp.Name, size, z, zopt)
+	    }
+	}
+    }
+}
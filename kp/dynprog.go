@@ -16,6 +16,10 @@ func DynProg(kp KnapsackProblem) ([]int,int) {
 	vv []int			// value function for item i+1
     )
 
+    if x,z,ok := solveFixed(kp, DynProg); ok {	// fixed items: solve the reduced problem
+	return x,z
+    }
+
     n := kp.N()				// n is the number of items we have
     x := make([]int,n)			// X[i] = 0 for i=0,...,n-1
     c := kp.Capacity()
@@ -42,7 +46,7 @@ func DynProg(kp KnapsackProblem) ([]int,int) {
     }
 
     // Forward computation
-    z := v[c]			// maximum 
+    z := vv[c]			// maximum (vv = v, also for n = 0)
     s := c			// go through the optimal decision starting with Capacity
     for i:=0 ; i<n ; i++ {
 	x[i] = policy[i][s]	// if the optimal decision is to select item i
@@ -53,3 +57,132 @@ func DynProg(kp KnapsackProblem) ([]int,int) {
 
     return x,z
 }
+
+// Value function of the dynamic programming recursion.
+// v[i][s] is the maximal profit of the items i,...,n-1 for the rest capacity s,
+// i.e. v[0][Capacity] is the optimal objective function value and v[n][s] = 0.
+func valueTable(kp KnapsackProblem) [][]int {
+    n := kp.N()
+    c := kp.Capacity()
+    v := make([][]int, n+1)
+    v[n] = make([]int, c+1)
+    for i:=n-1 ; i>=0 ; i-- {			// backward computation as in DynProg()
+	v[i] = make([]int, c+1)
+	for s:=0 ; s<=c ; s++ {
+	    v[i][s] = v[i+1][s]
+	    if s >= kp.Weight(i) && v[i][s] < kp.Profit(i) + v[i+1][s-kp.Weight(i)] {
+		v[i][s] = kp.Profit(i) + v[i+1][s-kp.Weight(i)]
+	    }
+	}
+    }
+    return v
+}
+
+// Enumerate all optimal solutions of a knapsack problem.
+//
+// DynProg() picks only one optimal decision for each item and rest capacity.
+// Here we keep the whole value function and follow every decision which
+// attains the optimal value: X[i]=0 if v[i+1][s] = v[i][s] and X[i]=1 if
+// Profit[i] + v[i+1][s-Weight[i]] = v[i][s].
+// At most max solutions are returned (all solutions if max <= 0).
+// The second result is the optimal objective function value.
+// An error is returned if the items fixed to 1 don't fit (see FixedItemsProblem).
+func EnumOptimal(kp KnapsackProblem, max int) ([][]int,int,error) {
+    var (
+	sols [][]int
+	enum func(i int, s int)
+    )
+
+    if r, err := fixedReduction(kp); err != nil {	// fixed items don't fit
+	return nil,0,err
+    } else if r != nil {			// fixed items: solve the reduced problem
+	xs,z,_ := EnumOptimal(r.Problem(), max)	// no fixed items, no error
+	for k,x := range xs {
+	    xs[k],_ = r.Expand(x,z)
+	}
+	return xs, z + r.psum, nil
+    }
+
+    n := kp.N()
+    v := valueTable(kp)
+    x := make([]int,n)
+
+    enum = func(i int, s int) {		// enumerate the decisions for items i,...,n-1
+	if max > 0 && len(sols) >= max {
+	    return
+	}
+	if i == n {			// a complete optimal solution
+	    sol := make([]int,n)
+	    copy(sol,x)
+	    sols = append(sols,sol)
+	    return
+	}
+	if s >= kp.Weight(i) && kp.Profit(i) + v[i+1][s-kp.Weight(i)] == v[i][s] {
+	    x[i] = 1			// X[i] = 1 is optimal
+	    enum(i+1, s-kp.Weight(i))
+	}
+	if v[i+1][s] == v[i][s] {	// X[i] = 0 is optimal
+	    x[i] = 0
+	    enum(i+1, s)
+	}
+    }
+    enum(0, kp.Capacity())
+
+    return sols, v[0][kp.Capacity()], nil
+}
+
+// A breakpoint of the capacity curve
+type Breakpoint struct {
+    Capacity int `json:"capacity"`	// smallest capacity with
+    Z        int `json:"z"`		// optimal objective function value z
+}
+
+// Optimal objective function value as a function of the capacity.
+// curve[s] is the optimal value of the knapsack problem with capacity s
+// for s=0,...,Capacity. This is the value function of DynProg() for item 0,
+// we only keep two rows of it, so we need O(Capacity) memory.
+func CapacityCurve(kp KnapsackProblem) []int {
+    if r, err := fixedReduction(kp); r != nil || err != nil {	// fixed items: the curve of the
+	curve := make([]int, kp.Capacity()+1)			// reduced problem, shifted by the
+	for s:=range curve {					// weight of the items fixed to 1,
+	    curve[s] = -1					// -1 if they don't fit
+	}
+	if err != nil {
+	    return curve
+	}
+	wfix := kp.Capacity() - r.Problem().Capacity()
+	for s,z := range CapacityCurve(r.Problem()) {
+	    curve[wfix+s] = z + r.psum
+	}
+	return curve
+    }
+
+    n := kp.N()
+    c := kp.Capacity()
+    v := make([]int, c+1)
+    vv := make([]int, c+1)
+    for i:=n-1 ; i>=0 ; i-- {			// backward computation as in DynProg()
+	for s:=0 ; s<=c ; s++ {
+	    v[s] = vv[s]
+	    if s >= kp.Weight(i) && v[s] < kp.Profit(i) + vv[s-kp.Weight(i)] {
+		v[s] = kp.Profit(i) + vv[s-kp.Weight(i)]
+	    }
+	}
+	v, vv = vv, v
+    }
+    return vv
+}
+
+// Compress a capacity curve to its breakpoints, i.e. the capacities where
+// the optimal value increases. The curve is a step function, so the optimal
+// value for capacity s is the value of the last breakpoint with capacity <= s.
+// The first breakpoint is always at capacity 0.
+func CapacityBreakpoints(curve []int) []Breakpoint {
+    var bps []Breakpoint
+    for s,z := range curve {
+	if s == 0 || z > curve[s-1] {
+	    bps = append(bps, Breakpoint{ Capacity: s, Z: z })
+	}
+    }
+    return bps
+}
@@ -0,0 +1,74 @@
+package kp
+
+import (
+    "fmt"
+    "testing"
+)
+
+// All optimal solutions by complete enumeration, as strings.
+func enumerateOptimal(kp KnapsackProblem) map[string]bool {
+    zopt := enumerate(kp)
+    sols := make(map[string]bool)
+    allSolutions(kp.N(), func(x []int) {
+	if objective(kp, x) == zopt {
+	    sols[fmt.Sprint(x)] = true
+	}
+    })
+    return sols
+}
+
+func TestEnumOptimal(t *testing.T) {
+    for _,kp := range testProblems(t) {
+	want := enumerateOptimal(kp)
+	xs,z,err := EnumOptimal(kp, 0)
+	if err != nil {
+	    t.Fatal(err)
+	}
+	got := make(map[string]bool)
+	for _,x := range xs {
+	    checkOptimal(t, "enum", kp, x, z)
+	    if got[fmt.Sprint(x)] {
+		t.Errorf("%s: %v is enumerated twice", kp.Name, x)
+	    }
+	    got[fmt.Sprint(x)] = true
+	}
+	if len(got) != len(want) {
+	    t.Errorf("%s: %v optimal solutions instead of %v", kp.Name, len(got), len(want))
+	}
+
+	if xs,_,_ = EnumOptimal(kp, 1); len(xs) != 1 {
+	    t.Errorf("%s: max = 1, but %v solutions", kp.Name, len(xs))
+	}
+    }
+}
+
+func TestCapacityCurve(t *testing.T) {
+    for _,kp := range testProblems(t) {
+	curve := CapacityCurve(kp)
+	if len(curve) != kp.C+1 {
+	    t.Fatalf("%s: curve has %v values instead of %v", kp.Name, len(curve), kp.C+1)
+	}
+	for s := range curve {
+	    sub := kp
+	    sub.C = s
+	    if zopt := enumerate(sub); curve[s] != zopt {
+		t.Errorf("%s: value %v for capacity %v, but the optimum is %v", kp.Name, curve[s], s, zopt)
+	    }
+	}
+
+	bps := CapacityBreakpoints(curve)
+	if len(bps) == 0 || bps[0].Capacity != 0 {
+	    t.Errorf("%s: first breakpoint %v", kp.Name, bps)
+	    continue
+	}
+	k := 0				// the step function of the breakpoints
+	for s,z := range curve {	// reproduces the curve
+	    for k+1 < len(bps) && bps[k+1].Capacity <= s {
+		k++
+	    }
+	    if bps[k].Z != z {
+		t.Errorf("%s: breakpoints give %v for capacity %v instead of %v", kp.Name, bps[k].Z, s, z)
+	    }
+	}
+    }
+}
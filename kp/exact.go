@@ -0,0 +1,149 @@
+package kp
+
+import (
+    "errors"
+)
+
+var errNoExactFill = errors.New("infeasible: no subset of the items fills the capacity exactly")
+
+// Solve a knapsack problem with dynamic programming, but only solutions which
+// fill the capacity exactly are feasible.
+//
+// v[i][s] is the maximal profit of the items i,...,n-1 with total weight
+// exactly s (none if there is no such subset), v[n][0] = 0.
+// An error is returned if no subset of the items fills the capacity exactly.
+func DynProgExact(kp KnapsackProblem) ([]int,int,error) {
+    if r, err := fixedReduction(kp); r != nil || err != nil {	// fixed items: solve the reduced problem
+	if err != nil {
+	    return nil,0,err
+	}
+	return solveReduced(r, DynProgExact)
+    }
+
+    const none = -1			// no subset with this weight
+
+    n := kp.N()
+    c := kp.Capacity()
+
+    policy := makePolicyTable(n, c)	// policy[i][s] stores the optimal decision
+    vv := make([]int, c+1)		// value function for item i+1
+    for s:=1 ; s<=c ; s++ {
+	vv[s] = none
+    }
+    for i:=n-1 ; i>=0 ; i-- {
+	v := make([]int, c+1)		// value function for item i
+	for s:=0 ; s<=c ; s++ {
+	    v[s] = vv[s]
+	    if s >= kp.Weight(i) && vv[s-kp.Weight(i)] != none &&
+	       v[s] < kp.Profit(i) + vv[s-kp.Weight(i)] {
+		v[s] = kp.Profit(i) + vv[s-kp.Weight(i)]
+		policy[i][s] = 1
+	    }
+	}
+	vv = v
+    }
+    if vv[c] == none {
+	return nil,0,errNoExactFill
+    }
+
+    x := make([]int,n)			// forward computation
+    s := c
+    for i:=0 ; i<n ; i++ {
+	x[i] = policy[i][s]
+	if x[i] == 1 {
+	    s -= kp.Weight(i)
+	}
+    }
+    return x,vv[c],nil
+}
+
+// Subset-sum problem data: only weights are given, the profit of an item is
+// its weight. So the optimal solution is the largest sum of weights which
+// doesn't exceed the capacity. All solvers for knapsack problems can be used.
+type SubsetSumData struct {
+    Name    string `json:"name,omitempty"`	// problem name, optional
+    Comment string `json:"comment,omitempty"`	// comment, optional
+    Type    string `json:"type"`		// problem type, unused at the moment
+    Dim     int    `json:"dimension"`		// problem size
+    W       []int  `json:"weights"`		// weight values
+    C       int    `json:"capacity"`		// capacity of the knapsack
+    X       []int  `json:"x,omitempty"`	// binary decision variables
+    Z       int    `json:"z,omitempty"`	// largest sum of weights
+}
+
+func (kp SubsetSumData) N() int {
+    return kp.Dim
+}
+
+func (kp SubsetSumData) Capacity() int {
+    return kp.C
+}
+
+func (kp SubsetSumData) Profit(i int) int {
+    return kp.W[i]
+}
+
+func (kp SubsetSumData) Weight(i int) int {
+    return kp.W[i]
+}
+
+// Check the sizes and weights of a subset-sum problem.
+func CheckSubsetSumData(kp *SubsetSumData) error {
+    if len(kp.W) != kp.Dim {
+	return errors.New("wrong input: weights and problem have different sizes")
+    }
+    if kp.C < 0 {
+	return errors.New("wrong input: negative capacity")
+    }
+    for _,w := range kp.W {
+	if w < 0 {
+	    return errors.New("wrong input: negative weight")
+	}
+    }
+    return nil
+}
+
+// Solve a subset-sum problem: the largest sum of weights which doesn't exceed
+// the capacity (profits are ignored).
+//
+// We only compute which sums are reachable. For every sum s we store the item
+// which made s reachable first (items in the order 0,...,n-1): s-Weight[i]
+// was reachable with the items before item i, so going back from the best sum
+// selects every item at most once. This needs O(Capacity) memory instead of
+// the O(n*Capacity) policy table of DynProg().
+func SubsetSum(kp KnapsackProblem) ([]int,int) {
+    if x,z,ok := solveFixed(kp, SubsetSum); ok {	// fixed items: solve the reduced problem
+	return x,z
+    }
+
+    n := kp.N()
+    c := kp.Capacity()
+    x := make([]int,n)
+    first := make([]int, c+1)		// item which made sum s reachable first,
+    first[0] = n			// -1 if s is not reachable
+    for s:=1 ; s<=c ; s++ {
+	first[s] = -1
+    }
+    best := 0				// largest reachable sum
+    for i:=0 ; i<n && best<c ; i++ {
+	w := kp.Weight(i)
+	if w == 0 {
+	    continue
+	}
+	for s:=c ; s>=w ; s-- {		// downwards: item i is used at most once
+	    if first[s] < 0 && first[s-w] >= 0 {
+		first[s] = i
+		if s > best {
+		    best = s
+		}
+	    }
+	}
+    }
+
+    for s:=best ; s>0 ; {		// go back through the items
+	i := first[s]
+	x[i] = 1
+	s -= kp.Weight(i)
+    }
+    return x,best
+}
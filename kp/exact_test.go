@@ -0,0 +1,100 @@
+package kp
+
+import (
+    "fmt"
+    "testing"
+)
+
+// Objective function value of x, -1 if x is infeasible or doesn't fill the
+// capacity exactly.
+func objectiveExact(kp KnapsackProblem, x []int) int {
+    if len(x) != kp.N() {
+	return -1
+    }
+    w := 0
+    for i,xi := range x {
+	w += xi*kp.Weight(i)
+    }
+    if w != kp.Capacity() {
+	return -1
+    }
+    return objective(kp, x)
+}
+
+// Optimal objective function value of the solutions which fill the capacity
+// exactly by complete enumeration, false if there is no such solution.
+func enumerateExact(kp KnapsackProblem) (int,bool) {
+    best := enumerateBy(kp.N(), func(x []int) int { return objectiveExact(kp, x) })
+    return best, best >= 0
+}
+
+func TestExactFill(t *testing.T) {
+    solvers := []struct {
+	name  string
+	solve func(KnapsackProblem) ([]int,int,error)
+    }{
+	{ "dp", DynProgExact },
+	{ "bab", func(kp KnapsackProblem) ([]int,int,error) { return BranchAndBoundOpt(kp, BabOptions{ ExactFill: true }) } },
+	{ "hs", func(kp KnapsackProblem) ([]int,int,error) { return BranchAndBoundHSOpt(kp, BabOptions{ ExactFill: true }) } },
+	{ "hs, no warm start", func(kp KnapsackProblem) ([]int,int,error) {
+	    return BranchAndBoundHSOpt(kp, BabOptions{ ExactFill: true, NoWarmStart: true })
+	} },
+    }
+    for _,kp := range testProblems(t) {
+	for _,c := range []int{ kp.C, kp.C+1, kp.C+7 } {
+	    kp.C = c
+	    zopt, feasible := enumerateExact(kp)
+	    for _,s := range solvers {
+		x,z,err := s.solve(kp)
+		if !feasible {
+		    if err == nil {
+			t.Errorf("%s, %s, capacity %v: no error, but no subset fills the capacity", s.name, kp.Name, c)
+		    }
+		    continue
+		}
+		if err != nil {
+		    t.Errorf("%s, %s, capacity %v: %v", s.name, kp.Name, c, err)
+		    continue
+		}
+		value := func(x []int) int { return objectiveExact(kp, x) }
+		checkSolution(t, fmt.Sprintf("%s, %s, capacity %v", s.name, kp.Name, c), value, x, z, zopt, true)
+	    }
+	}
+    }
+}
+
+func TestSubsetSum(t *testing.T) {
+    for _,kp := range testProblems(t) {
+	ssp := SubsetSumData{ Name: kp.Name, Dim: kp.Dim, W: kp.W, C: kp.C }
+	if err := CheckSubsetSumData(&ssp); err != nil {
+	    t.Fatal(err)
+	}
+	x,z := SubsetSum(ssp)
+	checkSolution(t, kp.Name, func(x []int) int { return objective(ssp, x) }, x, z, enumerate(ssp), true)
+
+	fixed := KnapsackData{ Name: kp.Name, Dim: kp.Dim, P: kp.W, W: kp.W, C: kp.C, Fix: make([]int, kp.Dim) }
+	fixed.Fix[0] = FixOut			// fixed items are respected
+	if zopt, feasible := enumerateFixed(fixed); feasible {
+	    x,z = SubsetSum(fixed)
+	    checkSolution(t, kp.Name + ", fixed", func(x []int) int { return objectiveFixed(fixed, x) }, x, z, zopt, true)
+	}
+    }
+}
+
+func TestCheckSubsetSumData(t *testing.T) {
+    tests := []struct {
+	name string
+	ssp  SubsetSumData
+	ok   bool
+    }{
+	{ "valid", SubsetSumData{ Dim: 2, W: []int{3,0}, C: 2 }, true },
+	{ "size", SubsetSumData{ Dim: 2, W: []int{3}, C: 2 }, false },
+	{ "negative capacity", SubsetSumData{ Dim: 1, W: []int{3}, C: -2 }, false },
+	{ "negative weight", SubsetSumData{ Dim: 1, W: []int{-3}, C: 2 }, false },
+    }
+    for _,test := range tests {
+	if err := CheckSubsetSumData(&test.ssp); (err == nil) != test.ok {
+	    t.Errorf("%s: %v", test.name, err)
+	}
+    }
+}
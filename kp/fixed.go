@@ -0,0 +1,317 @@
+package kp
+
+import (
+    "errors"
+    "fmt"
+    "math/big"
+)
+
+// Markers for fixed items (KnapsackData.Fix)
+const (
+    FixFree = 0		// the solver decides
+    FixIn   = 1		// the item must be selected
+    FixOut  = -1	// the item must not be selected
+)
+
+// A knapsack problem with fix markers (KnapsackData implements it by Fix).
+//
+// The exported solvers for the 0/1 knapsack problem check whether their
+// problem implements this interface. If it has fixed items, they solve the
+// reduced problem (see Reduce()) and expand the result, so the fix markers
+// are always respected. Solvers with an error result return an error if the
+// items fixed to 1 don't fit into the knapsack. The solvers with the result
+// ([]int,int), e.g. DynProg() or Greedy(), return a nil solution then,
+// SolveFixed() applies them with an error result. Use CheckFixedItems() to
+// check this in advance.
+//
+// The solvers for the other problem types (bounded, unbounded, multiple-choice
+// and multiple knapsack problem) don't support fixed items, they return an
+// error if there are any (see noFixedItems()).
+type FixedItemsProblem interface {
+    KnapsackProblem
+    FixedItems() []int		// fix markers, nil if there are no fixed items
+}
+
+// Fix markers of the items.
+func (kp KnapsackData) FixedItems() []int {
+    return kp.Fix
+}
+
+// Check the fix markers of a knapsack problem: the markers must be valid
+// and the items fixed to 1 must fit into the knapsack.
+func CheckFixedItems(kp *KnapsackData) error {
+    if kp.Fix == nil {
+	return nil
+    }
+    _,err := Reduce(kp, kp.Fix)
+    return err
+}
+
+// A knapsack problem with fixed items reduced to its free items.
+//
+// The free items keep their order, so the reduced problem satisfies the
+// precondition of sorted items if the original problem does. The capacity of
+// the reduced problem is the residual capacity after packing the items fixed
+// to 1. Any solver can be applied to the reduced problem and its solution is
+// expanded to a solution of the original problem by Expand().
+type Reduction struct {
+    fix  []int		// fix markers of the original problem
+    view itemView	// the free items with residual capacity
+    psum int		// profit sum of the items fixed to 1
+}
+
+// Reduce a knapsack problem with fix markers (FixFree, FixIn, FixOut)
+// to its free items. fix may be nil (no fixed items).
+// An error is returned if the items fixed to 1 don't fit into the knapsack.
+func Reduce(kp KnapsackProblem, fix []int) (*Reduction,error) {
+    n := kp.N()
+    if fix == nil {
+	fix = make([]int,n)
+    }
+    if len(fix) != n {
+	return nil, fmt.Errorf("fix markers and problem have different sizes: %v, %v", len(fix), n)
+    }
+
+    r := &Reduction{ fix: fix, view: itemView{ kp: kp, c: kp.Capacity() } }
+    for i:=0 ; i<n ; i++ {
+	switch fix[i] {
+	case FixFree:
+	    r.view.items = append(r.view.items, i)
+	case FixIn:
+	    r.psum += kp.Profit(i)
+	    r.view.c -= kp.Weight(i)
+	case FixOut:
+	default:
+	    return nil, fmt.Errorf("wrong fix marker for item %v: %v", i, fix[i])
+	}
+    }
+    if r.view.c < 0 {
+	return nil, fmt.Errorf("infeasible: items fixed to 1 exceed the capacity by %v", -r.view.c)
+    }
+
+    return r, nil
+}
+
+// The reduced problem (free items only).
+func (r *Reduction) Problem() KnapsackProblem {
+    return r.view
+}
+
+// The reduced problem as KnapsackData (free items only).
+func (r *Reduction) Data() KnapsackData {
+    n := r.view.N()
+    kpd := KnapsackData{ Type: "KP", Dim: n, C: r.view.c, P: make([]int,n), W: make([]int,n) }
+    for i:=0 ; i<n ; i++ {
+	kpd.P[i] = r.view.Profit(i)
+	kpd.W[i] = r.view.Weight(i)
+    }
+    return kpd
+}
+
+// Restrict a solution of the original problem to the free items.
+func (r *Reduction) Restrict(x []int) []int {
+    y := make([]int, len(r.view.items))
+    for j,i := range r.view.items {
+	y[j] = x[i]
+    }
+    return y
+}
+
+// Expand a solution x of the reduced problem with objective function value z
+// to a solution of the original problem.
+func (r *Reduction) Expand(x []int, z int) ([]int,int) {
+    y := make([]int, len(r.fix))
+    for i,f := range r.fix {
+	if f == FixIn {
+	    y[i] = 1
+	}
+    }
+    for j,i := range r.view.items {
+	y[i] = x[j]
+    }
+    return y, z + r.psum
+}
+
+// Expand a fractional solution x of the reduced problem with bound value z
+// to the original problem.
+func (r *Reduction) ExpandFrac(x []float64, z int) ([]float64,int) {
+    y := make([]float64, len(r.fix))
+    for i,f := range r.fix {
+	if f == FixIn {
+	    y[i] = 1.0
+	}
+    }
+    for j,i := range r.view.items {
+	y[i] = x[j]
+    }
+    return y, z + r.psum
+}
+
+// Expand the LP relaxation of the reduced problem to the original problem.
+// Fixed items have no reduced costs (nil).
+func (r *Reduction) ExpandLP(lp LPRelaxation) LPRelaxation {
+    elp := LPRelaxation{ Dual: lp.Dual, ReducedCosts: make([]*big.Rat, len(r.fix)) }
+    elp.Z = new(big.Rat).Add(lp.Z, big.NewRat(int64(r.psum), 1))
+    elp.Critical = len(r.fix)
+    if lp.Critical < len(r.view.items) {
+	elp.Critical = r.view.items[lp.Critical]
+    }
+    for j,i := range r.view.items {
+	elp.ReducedCosts[i] = lp.ReducedCosts[j]
+    }
+    return elp
+}
+
+// Solve a knapsack problem with fixed items by any solver for the 0/1
+// knapsack problem. The solver is applied to the reduced problem.
+func SolveFixed(kp KnapsackProblem, fix []int, solve func(KnapsackProblem) ([]int,int)) ([]int,int,error) {
+    r, err := Reduce(kp, fix)
+    if err != nil {
+	return nil,0,err
+    }
+    x,z := solve(r.Problem())
+    x,z = r.Expand(x,z)
+    return x,z,nil
+}
+
+// Reduction of a knapsack problem by its fix markers (see FixedItemsProblem),
+// nil if the problem has no fixed items.
+func fixedReduction(kp KnapsackProblem) (*Reduction,error) {
+    fp, ok := kp.(FixedItemsProblem)
+    if !ok {
+	return nil,nil
+    }
+    fix := fp.FixedItems()
+    for _,f := range fix {
+	if f != FixFree {
+	    return Reduce(kp, fix)
+	}
+    }
+    return nil,nil
+}
+
+// Apply a solver to the reduced problem if kp has fixed items. The last
+// result is false if kp has no fixed items, then the caller solves kp itself.
+// If the items fixed to 1 don't fit, the solution is nil.
+func solveFixed(kp KnapsackProblem, solve func(KnapsackProblem) ([]int,int)) ([]int,int,bool) {
+    r, err := fixedReduction(kp)
+    if err != nil {
+	return nil,0,true
+    }
+    if r == nil {
+	return nil,0,false
+    }
+    x,z := solve(r.Problem())
+    x,z = r.Expand(x,z)
+    return x,z,true
+}
+
+// Apply a solver with an error result to the reduced problem r and expand
+// its solution.
+func solveReduced(r *Reduction, solve func(KnapsackProblem) ([]int,int,error)) ([]int,int,error) {
+    x,z,err := solve(r.Problem())
+    if err != nil {
+	return nil,0,err
+    }
+    x,z = r.Expand(x,z)
+    return x,z,nil
+}
+
+// Branch and bound for the reduced problem r: the incumbent is restricted to
+// the free items and the solution is expanded.
+func solveReducedBab(r *Reduction, opt BabOptions, bab func(KnapsackProblem, BabOptions) ([]int,int,error)) ([]int,int,error) {
+    if opt.Incumbent != nil {
+	if len(opt.Incumbent) != len(r.fix) {
+	    return nil,0,errors.New("incumbent and problem have different sizes")
+	}
+	opt.Incumbent = r.Restrict(opt.Incumbent)
+    }
+    x,z,err := solveReduced(r, func(p KnapsackProblem) ([]int,int,error) { return bab(p, opt) })
+    if err == nil && opt.Certificate != nil {	// the certificate refers to the free items
+	opt.Certificate.Fix = r.certFix()
+    }
+    return x,z,err
+}
+
+// Expand solutions of the reduced problem to the original problem.
+func (r *Reduction) expandAll(sols []Solution) []Solution {
+    for k,sol := range sols {
+	sols[k].X, sols[k].Z = r.Expand(sol.X, sol.Z)
+    }
+    return sols
+}
+
+// Sensitivity analysis for a solution x of the original problem: the ranges
+// of the free items are computed for the reduced problem, the ranges of the
+// fixed items are unbounded.
+func (r *Reduction) sensitivity(x []int) ([]ProfitRange,error) {
+    if err := r.checkSolution(x); err != nil {
+	return nil, err
+    }
+    sub, err := Sensitivity(r.Problem(), r.Restrict(x))
+    if err != nil {
+	return nil, err
+    }
+    ranges := make([]ProfitRange, len(r.fix))
+    for i := range ranges {
+	ranges[i] = ProfitRange{ Item: i, X: x[i] }
+    }
+    for j,i := range r.view.items {
+	ranges[i].Min, ranges[i].Max = sub[j].Min, sub[j].Max
+    }
+    return ranges, nil
+}
+
+// Check that a solution x of the original problem respects the fix markers.
+func (r *Reduction) checkSolution(x []int) error {
+    for i,f := range r.fix {
+	if (f == FixIn && x[i] != 1) || (f == FixOut && x[i] != 0) {
+	    return errors.New("solution violates the fixed items")
+	}
+    }
+    return nil
+}
+
+// Fix markers of a certificate for the reduction r (nil: no fixed items).
+func (r *Reduction) certFix() []int {
+    if r == nil {
+	return nil
+    }
+    return append([]int(nil), r.fix...)
+}
+
+// Compare the fix markers of a certificate with those of the reduction r.
+// nil stands for a problem without fixed items.
+func (r *Reduction) sameFix(fix []int) bool {
+    if r == nil {
+	for _,f := range fix {
+	    if f != FixFree {
+		return false
+	    }
+	}
+	return true
+    }
+    if len(fix) != len(r.fix) {
+	return false
+    }
+    for i,f := range r.fix {
+	if fix[i] != f {
+	    return false
+	}
+    }
+    return true
+}
+
+// Solvers for other problem types which don't support fixed items check
+// their problem with noFixedItems(). kp is any problem type, KnapsackData
+// implements all of them.
+func noFixedItems(kp interface{}) error {
+    if fp, ok := kp.(FixedItemsProblem); ok {
+	for _,f := range fp.FixedItems() {
+	    if f != FixFree {
+		return errors.New("fixed items are not supported for this problem type")
+	    }
+	}
+    }
+    return nil
+}
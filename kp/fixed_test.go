@@ -0,0 +1,195 @@
+package kp
+
+import (
+    "fmt"
+    "math/rand"
+    "testing"
+)
+
+// Objective function value of x, -1 if x is infeasible or violates the fix
+// markers.
+func objectiveFixed(kp KnapsackData, x []int) int {
+    for i,f := range kp.Fix {
+	if len(x) == kp.N() && ((f == FixIn && x[i] != 1) || (f == FixOut && x[i] != 0)) {
+	    return -1
+	}
+    }
+    return objective(kp, x)
+}
+
+// Optimal objective function value of a problem with fixed items by complete
+// enumeration, -1 and false if the items fixed to 1 don't fit.
+func enumerateFixed(kp KnapsackData) (int,bool) {
+    best := enumerateBy(kp.N(), func(x []int) int { return objectiveFixed(kp, x) })
+    return best, best >= 0
+}
+
+// Solver without an error result, it returns a nil solution if the fixed
+// items don't fit.
+func noError(solve func(KnapsackProblem) ([]int,int)) func(KnapsackProblem) ([]int,int,error) {
+    return func(kp KnapsackProblem) ([]int,int,error) {
+	x,z := solve(kp)
+	return x,z,nil
+    }
+}
+
+// Exact and heuristic solvers for the 0/1 knapsack problem, which have to
+// respect the fix markers. fails: the solver returns an error if the fixed
+// items don't fit.
+var fixedSolvers = []struct {
+    name  string
+    exact bool
+    fails bool
+    solve func(kp KnapsackProblem) ([]int,int,error)
+}{
+    { "dp", true, false, noError(DynProg) },
+    { "bab", true, false, noError(BranchAndBound) },
+    { "hs", true, false, noError(BranchAndBoundHS) },
+    { "greedy", false, false, noError(Greedy) },
+    { "dualgreedy", false, false, noError(DualGreedy) },
+    { "extgreedy", false, false, noError(ExtGreedy) },
+    { "tabu", false, true, func(kp KnapsackProblem) ([]int,int,error) { return TabuSearch(kp, TabuParams{}) } },
+    { "ga", false, true, func(kp KnapsackProblem) ([]int,int,error) {
+	return Genetic(kp, GeneticParams{ PopSize: 10, Generations: 20, Workers: 1, Seed: 1 })
+    } },
+    { "grasp", false, true, func(kp KnapsackProblem) ([]int,int,error) {
+	return Grasp(kp, GraspParams{ Restarts: 5, Workers: 1, Seed: 1 })
+    } },
+    { "sa", false, true, func(kp KnapsackProblem) ([]int,int,error) {
+	return SimAnneal(kp, AnnealParams{ Iterations: 200, Seed: 1 })
+    } },
+    { "localsearch", false, true, func(kp KnapsackProblem) ([]int,int,error) {
+	return LocalSearch(kp, make([]int, kp.N()))
+    } },
+    { "kbest", true, true, func(kp KnapsackProblem) ([]int,int,error) {
+	sols, err := KBestHS(kp, 2)
+	if err != nil || len(sols) == 0 {
+	    return nil,0,err
+	}
+	return sols[0].X, sols[0].Z, nil
+    } },
+    { "certificate", true, true, func(kp KnapsackProblem) ([]int,int,error) {
+	x,z,_,err := DynProgCertificate(kp)
+	return x,z,err
+    } },
+    { "session", true, true, func(kp KnapsackProblem) ([]int,int,error) {
+	s, err := NewSession(kp)
+	if err != nil {
+	    return nil,0,err
+	}
+	x,z := s.Solve()
+	return x,z,nil
+    } },
+}
+
+func TestFixedItems(t *testing.T) {
+    r := rand.New(rand.NewSource(1))
+    for _,kp := range testProblems(t) {
+	for k:=0 ; k<5 ; k++ {
+	    kp.Fix = make([]int, kp.N())
+	    for i := range kp.Fix {
+		kp.Fix[i] = r.Intn(3) - 1	// FixOut, FixFree or FixIn
+	    }
+	    zopt, feasible := enumerateFixed(kp)
+	    if err := CheckFixedItems(&kp); (err == nil) != feasible {
+		t.Errorf("%s, fix %v: CheckFixedItems() gives %v", kp.Name, kp.Fix, err)
+	    }
+
+	    for _,s := range fixedSolvers {
+		x,z,err := s.solve(kp)
+		if !feasible {
+		    if x != nil || (err == nil) == s.fails {
+			t.Errorf("%s, %s, fix %v: solution %v, error %v, but the fixed items don't fit", s.name, kp.Name, kp.Fix, x, err)
+		    }
+		    continue
+		}
+		if err != nil {
+		    t.Fatalf("%s, %s, fix %v: %v", s.name, kp.Name, kp.Fix, err)
+		}
+		value := func(x []int) int { return objectiveFixed(kp, x) }
+		checkSolution(t, fmt.Sprintf("%s, %s, fix %v", s.name, kp.Name, kp.Fix), value, x, z, zopt, s.exact)
+	    }
+
+	    if _,_,err := BranchAndBoundOpt(kp, BabOptions{}); (err == nil) != feasible {
+		t.Errorf("%s, fix %v: BranchAndBoundOpt() gives %v", kp.Name, kp.Fix, err)
+	    }
+	    if _,_,err := EnumOptimal(kp, 1); (err == nil) != feasible {
+		t.Errorf("%s, fix %v: EnumOptimal() gives %v", kp.Name, kp.Fix, err)
+	    }
+	    if _,_,err := LPRelax(kp); (err == nil) != feasible {
+		t.Errorf("%s, fix %v: LPRelax() gives %v", kp.Name, kp.Fix, err)
+	    }
+	    if curve := CapacityCurve(kp); curve[kp.C] != zopt {	// -1 if infeasible
+		t.Errorf("%s, fix %v: capacity curve gives %v", kp.Name, kp.Fix, curve[kp.C])
+	    }
+	}
+    }
+}
+
+func TestNoFixedItems(t *testing.T) {
+    kp := KnapsackData{ Name: "other types", Dim: 4, P: []int{6,10,12,7}, W: []int{1,2,3,2}, C: 5,
+			Bounds: []int{2,1,1,3}, Classes: []int{0,0,1,1}, Capacities: []int{3,2},
+			Fix: []int{FixFree,FixIn,FixFree,FixFree} }
+    solvers := []struct {
+	name  string
+	solve func() error
+    }{
+	{ "bounded dp", func() error { _,_,err := BoundedDynProg(kp); return err } },
+	{ "bounded bab", func() error { _,_,err := BoundedBranchAndBound(kp); return err } },
+	{ "unbounded dp", func() error { _,_,err := UnboundedDynProg(kp); return err } },
+	{ "unbounded bab", func() error { _,_,err := UnboundedBranchAndBound(kp); return err } },
+	{ "mc ub", func() error { _,_,err := MCUpperBound(kp); return err } },
+	{ "mc dp", func() error { _,_,err := MCDynProg(kp); return err } },
+	{ "mc bab", func() error { _,_,err := MCBranchAndBound(kp); return err } },
+	{ "mkp ub", func() error { _,err := MKPUpperBound(kp); return err } },
+	{ "mkp greedy", func() error { _,_,err := MKPGreedy(kp); return err } },
+	{ "mkp bab", func() error { _,_,err := MKPBoundAndBound(kp); return err } },
+    }
+    for _,s := range solvers {
+	if err := s.solve(); err == nil {
+	    t.Errorf("%s: fixed items are accepted", s.name)
+	}
+    }
+
+    kp.Fix = []int{FixFree,FixFree,FixFree,FixFree}	// only free items
+    for _,s := range solvers {
+	if err := s.solve(); err != nil {
+	    t.Errorf("%s, free items: %v", s.name, err)
+	}
+    }
+}
+
+func TestSessionFixItem(t *testing.T) {
+    kp := KnapsackData{ Name: "session", Dim: 3, P: []int{6,10,12}, W: []int{1,2,3}, C: 5 }
+    s, err := NewSession(kp)
+    if err != nil {
+	t.Fatal(err)
+    }
+    steps := []struct {
+	item, fix int
+	ok        bool
+	z         int
+    }{
+	{ 0, FixIn, true, 18 },		// 6 + 12
+	{ 2, FixOut, true, 16 },	// 6 + 10
+	{ 2, FixIn, true, 18 },
+	{ 1, FixIn, false, 18 },	// 1+2+3 > 5
+	{ 0, FixFree, true, 22 },
+	{ 1, 2, false, 22 },		// wrong marker
+    }
+    for _,step := range steps {
+	if err := s.FixItem(step.item, step.fix); (err == nil) != step.ok {
+	    t.Errorf("FixItem(%v, %v): %v", step.item, step.fix, err)
+	}
+	x,z := s.Solve()
+	p := s.Problem()
+	p.Name = kp.Name
+	checkFeasible(t, "session", p, x, z)
+	if z != step.z {
+	    t.Errorf("FixItem(%v, %v): z = %v instead of %v", step.item, step.fix, z, step.z)
+	}
+    }
+    if err := s.ChangeCapacity(2); err == nil {	// item 2 is fixed to 1
+	t.Error("ChangeCapacity(): no error for items fixed to 1")
+    }
+}
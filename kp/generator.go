@@ -17,9 +17,7 @@ func Generate(gen KnapsackGenData) (KnapsackData,error) {
     p := make([]int, gen.N)
     w := make([]int, gen.N)
 
-    if gen.Seed == 0 {
-	gen.Seed = int64(time.Now().Nanosecond())
-    }
+    gen.Seed = randomSeed(gen.Seed)
     rand.Seed(gen.Seed)
 
     for i:=0 ; i<gen.N ; i++ {		// weights are always uniformly distributed in
@@ -110,3 +108,13 @@ func permute(a []int, perm []int) []int {
     }
     return b
 }
+
+// Seed value for a random generator: a time based seed if seed is 0,
+// otherwise seed itself. All randomized functions of this package (e.g.
+// Generate(), SimAnneal()) treat a seed of 0 this way.
+func randomSeed(seed int64) int64 {
+    if seed == 0 {
+	return int64(time.Now().Nanosecond())
+    }
+    return seed
+}
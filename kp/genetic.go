@@ -0,0 +1,197 @@
+package kp
+
+import (
+    "math/rand"
+    "runtime"
+    "sync"
+)
+
+// Parameters of the genetic algorithm
+type GeneticParams struct {
+    PopSize     int	// population size, default: 100
+    Generations int	// number of generations, default: 500
+    Tournament  int	// tournament size for the selection of parents, default: 2
+    Mutation    float64	// mutation probability of a single bit, default: 1/n
+    Workers     int	// number of goroutines for the fitness evaluation,
+			// default: number of CPUs
+    Seed        int64	// seed value for the random generator, 0: time based seed
+}
+
+// An individual of the population
+type chromosome struct {
+    x []int		// binary decision variables (the genes)
+    z int		// fitness = objective function value
+}
+
+// Solve a knapsack problem by a genetic algorithm.
+//
+// The chromosomes are binary decision vectors. In each generation the parents
+// are chosen by tournament selection, a child is produced by uniform crossover
+// and mutation. Each child is repaired (if infeasible) and improved by the
+// ratio based operator of repairChromosome(). The best chromosome found so far
+// always survives (elitism).
+//
+// Precondition: Profit[i]/Weight[i] >= Profit[i-1]/Weight[i-1] for i=1,...,n-1
+//
+// We do not check the precondition here!
+// Without the precondition the repair operator still computes feasible
+// solutions, but they are usually considerably worse.
+//
+// Only the main goroutine draws random numbers and the fitness evaluation
+// is deterministic, so the result is reproducible for a given seed regardless
+// of the number of workers.
+func Genetic(kp KnapsackProblem, par GeneticParams) ([]int,int,error) {
+    if r, err := fixedReduction(kp); r != nil || err != nil {	// fixed items: solve the reduced problem
+	if err != nil {
+	    return nil,0,err
+	}
+	return solveReduced(r, func(p KnapsackProblem) ([]int,int,error) { return Genetic(p, par) })
+    }
+
+    n := kp.N()
+    if par.PopSize <= 1 {
+	par.PopSize = 100
+    }
+    if par.Generations <= 0 {
+	par.Generations = 500
+    }
+    if par.Tournament <= 0 {
+	par.Tournament = 2
+    }
+    if par.Mutation <= 0 && n > 0 {
+	par.Mutation = 1.0 / float64(n)
+    }
+    if par.Workers <= 0 {
+	par.Workers = runtime.NumCPU()
+    }
+    rnd := rand.New(rand.NewSource(randomSeed(par.Seed)))
+
+    pop := make([]chromosome, par.PopSize)	// random initial population
+    for k:=range pop {
+	pop[k].x = make([]int,n)
+	for i:=0 ; i<n ; i++ {
+	    pop[k].x[i] = rnd.Intn(2)
+	}
+    }
+    evaluate(kp, pop, par.Workers)
+    best := bestChromosome(pop)
+    xbest := make([]int,n)
+    copy(xbest, pop[best].x)
+    zbest := pop[best].z
+
+    children := make([]chromosome, par.PopSize)
+    for k:=range children {
+	children[k].x = make([]int,n)
+    }
+    for g:=0 ; g<par.Generations ; g++ {
+	for k:=range children {			// produce the children
+	    p1 := tournament(pop, par.Tournament, rnd)
+	    p2 := tournament(pop, par.Tournament, rnd)
+	    for i:=0 ; i<n ; i++ {
+		if rnd.Intn(2) == 0 {		// uniform crossover
+		    children[k].x[i] = pop[p1].x[i]
+		} else {
+		    children[k].x[i] = pop[p2].x[i]
+		}
+		if rnd.Float64() < par.Mutation {	// mutation
+		    children[k].x[i] = 1 - children[k].x[i]
+		}
+	    }
+	}
+	evaluate(kp, children, par.Workers)
+
+	worst := 0				// elitism: the best solution so far
+	for k:=range children {			// substitutes the worst child
+	    if children[k].z < children[worst].z {
+		worst = k
+	    }
+	}
+	copy(children[worst].x, xbest)
+	children[worst].z = zbest
+
+	pop, children = children, pop
+	if best = bestChromosome(pop); pop[best].z > zbest {
+	    copy(xbest, pop[best].x)
+	    zbest = pop[best].z
+	}
+    }
+
+    return xbest,zbest,nil
+}
+
+// Repair and evaluate all chromosomes of a population in parallel.
+func evaluate(kp KnapsackProblem, pop []chromosome, workers int) {
+    var wg sync.WaitGroup
+
+    chunk := (len(pop) + workers - 1) / workers
+    for lo:=0 ; lo<len(pop) ; lo+=chunk {
+	hi := lo + chunk
+	if hi > len(pop) {
+	    hi = len(pop)
+	}
+	wg.Add(1)
+	go func(part []chromosome) {
+	    defer wg.Done()
+	    for k:=range part {
+		part[k].z = repairChromosome(kp, part[k].x)
+	    }
+	}(pop[lo:hi])
+    }
+    wg.Wait()
+}
+
+// Ratio based repair and improvement operator.
+// Drop phase: as long as the knapsack overflows, we remove the item with the
+// least profit/weight value, i.e. the packed item with the largest index.
+// Add phase: we pack the remaining items in order of decreasing profit/weight
+// value (increasing index) if they fit.
+// Returns the objective function value of the repaired solution.
+func repairChromosome(kp KnapsackProblem, x []int) int {
+    n := kp.N()
+    c := kp.Capacity()
+    z := 0
+    w := 0
+    for i:=0 ; i<n ; i++ {
+	if x[i] == 1 {
+	    z += kp.Profit(i)
+	    w += kp.Weight(i)
+	}
+    }
+    for i:=n-1 ; i>=0 && w > c ; i-- {		// drop phase
+	if x[i] == 1 {
+	    x[i] = 0
+	    z -= kp.Profit(i)
+	    w -= kp.Weight(i)
+	}
+    }
+    for i:=0 ; i<n ; i++ {			// add phase
+	if x[i] == 0 && w+kp.Weight(i) <= c {
+	    x[i] = 1
+	    z += kp.Profit(i)
+	    w += kp.Weight(i)
+	}
+    }
+    return z
+}
+
+// Tournament selection: the best of size random chromosomes.
+func tournament(pop []chromosome, size int, rnd *rand.Rand) int {
+    k := rnd.Intn(len(pop))
+    for j:=1 ; j<size ; j++ {
+	if l := rnd.Intn(len(pop)); pop[l].z > pop[k].z {
+	    k = l
+	}
+    }
+    return k
+}
+
+// Index of the best chromosome of a population.
+func bestChromosome(pop []chromosome) int {
+    best := 0
+    for k:=range pop {
+	if pop[k].z > pop[best].z {
+	    best = k
+	}
+    }
+    return best
+}
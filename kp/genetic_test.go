@@ -0,0 +1,27 @@
+package kp
+
+import (
+    "testing"
+)
+
+func TestGenetic(t *testing.T) {
+    params := []GeneticParams{
+	{ PopSize: 20, Generations: 50, Seed: 1 },
+	{ PopSize: 10, Generations: 30, Tournament: 3, Mutation: 0.2, Seed: 5 },
+    }
+    for _,kp := range testProblems(t) {
+	for _,par := range params {
+	    par.Workers = 1
+	    x,z,err := Genetic(kp, par)
+	    if err != nil {
+		t.Fatal(err)
+	    }
+	    checkFeasible(t, "ga", kp, x, z)
+
+	    par.Workers = 4			// reproducible for any number of workers
+	    if _,z2,_ := Genetic(kp, par); z2 != z {
+		t.Errorf("%s, %+v: not reproducible, z = %v and %v", kp.Name, par, z, z2)
+	    }
+	}
+    }
+}
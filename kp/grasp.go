@@ -0,0 +1,114 @@
+package kp
+
+import (
+    "math/rand"
+    "runtime"
+    "sync"
+)
+
+// Parameters of the GRASP heuristic
+type GraspParams struct {
+    Restarts int	// number of constructed solutions, default: 100
+    RCLSize  int	// size of the restricted candidate list, default: 3
+    Workers  int	// number of goroutines, default: number of CPUs
+    Seed     int64	// seed value for the random generator, 0: time based seed
+}
+
+// Solve a knapsack problem by GRASP (greedy randomized adaptive search procedure).
+//
+// Each restart constructs a solution by a randomized greedy: the restricted
+// candidate list (RCL) contains the RCLSize items with the largest profit/weight
+// values which still fit into the knapsack, one of them is packed at random.
+// The constructed solution is improved by LocalSearch(). The restarts run in
+// parallel goroutines, which share the best solution found so far.
+//
+// Restart r uses its own random generator with seed Seed+r. If several restarts
+// find the best solution value, the solution of the first restart is returned.
+// So the result is reproducible for a given seed regardless of the number of
+// workers.
+func Grasp(kp KnapsackProblem, par GraspParams) ([]int,int,error) {
+    var (
+	mutex sync.Mutex	// protects the shared best solution
+	wg    sync.WaitGroup
+    )
+
+    if r, err := fixedReduction(kp); r != nil || err != nil {	// fixed items: solve the reduced problem
+	if err != nil {
+	    return nil,0,err
+	}
+	return solveReduced(r, func(p KnapsackProblem) ([]int,int,error) { return Grasp(p, par) })
+    }
+
+    n := kp.N()
+    if par.Restarts <= 0 {
+	par.Restarts = 100
+    }
+    if par.RCLSize <= 0 {
+	par.RCLSize = 3
+    }
+    if par.Workers <= 0 {
+	par.Workers = runtime.NumCPU()
+    }
+    par.Seed = randomSeed(par.Seed)
+
+    p := make([]int,n)			// items in order of decreasing profit/weight
+    w := make([]int,n)
+    for i:=0 ; i<n ; i++ {
+	p[i] = kp.Profit(i)
+	w[i] = kp.Weight(i)
+    }
+    order := sortPerm(p, w)
+
+    xbest,zbest := Greedy(kp)		// shared best solution
+    rbest := -1				// restart that found it (-1: Greedy)
+
+    restarts := make(chan int)
+    for k:=0 ; k<par.Workers ; k++ {
+	wg.Add(1)
+	go func() {
+	    defer wg.Done()
+	    for r := range restarts {
+		rnd := rand.New(rand.NewSource(par.Seed + int64(r)))
+		x,z,_ := LocalSearch(kp, graspConstruct(kp, order, par.RCLSize, rnd))	// binary, no error
+		mutex.Lock()
+		if z > zbest || (z == zbest && rbest >= 0 && r < rbest) {
+		    xbest, zbest, rbest = x, z, r
+		}
+		mutex.Unlock()
+	    }
+	}()
+    }
+    for r:=0 ; r<par.Restarts ; r++ {
+	restarts <- r
+    }
+    close(restarts)
+    wg.Wait()
+
+    return xbest,zbest,nil
+}
+
+// Randomized greedy construction of a solution.
+// order contains the items in order of decreasing profit/weight.
+func graspConstruct(kp KnapsackProblem, order []int, rclSize int, rnd *rand.Rand) []int {
+    n := kp.N()
+    x := make([]int,n)
+    c := kp.Capacity()
+    rcl := make([]int, 0, rclSize)
+    for {
+	rcl = rcl[:0]			// build the restricted candidate list
+	for _,i := range order {
+	    if x[i] == 0 && kp.Weight(i) <= c {
+		rcl = append(rcl, i)
+		if len(rcl) == rclSize {
+		    break
+		}
+	    }
+	}
+	if len(rcl) == 0 {		// no item fits anymore: we are done
+	    return x
+	}
+	i := rcl[rnd.Intn(len(rcl))]	// pack a random candidate
+	x[i] = 1
+	c -= kp.Weight(i)
+    }
+}
@@ -0,0 +1,28 @@
+package kp
+
+import (
+    "testing"
+)
+
+func TestGrasp(t *testing.T) {
+    params := []GraspParams{
+	{ Restarts: 20, Seed: 1 },
+	{ Restarts: 10, RCLSize: 1, Seed: 3 },
+	{ Restarts: 10, RCLSize: 5, Seed: 9 },
+    }
+    for _,kp := range testProblems(t) {
+	for _,par := range params {
+	    par.Workers = 1
+	    x,z,err := Grasp(kp, par)
+	    if err != nil {
+		t.Fatal(err)
+	    }
+	    checkFeasible(t, "grasp", kp, x, z)
+
+	    par.Workers = 4			// reproducible for any number of workers
+	    if x2,z2,_ := Grasp(kp, par); z2 != z || objective(kp, x2) != z {
+		t.Errorf("%s, %+v: not reproducible, z = %v and %v", kp.Name, par, z, z2)
+	    }
+	}
+    }
+}
@@ -11,6 +11,10 @@ package kp
 // To check the precondition, use kp.CheckSortedItems().
 //
 func Greedy(kp KnapsackProblem) ([]int,int) {
+    if x,z,ok := solveFixed(kp, Greedy); ok {	// fixed items: solve the reduced problem
+	return x,z
+    }
+
     n := kp.N()					// n is the number of items we have
     x := make([]int,n)				// X[i] = 0 for i=0,...,n-1
     c := kp.Capacity()
@@ -38,6 +42,10 @@ func Greedy(kp KnapsackProblem) ([]int,int) {
 // DualGreedy() computes a feasible solution even if the precondition is not valid,
 // but this solution is usually considerably worse.
 func DualGreedy(kp KnapsackProblem) ([]int,int) {
+    if x,z,ok := solveFixed(kp, DualGreedy); ok {	// fixed items: solve the reduced problem
+	return x,z
+    }
+
     n := kp.N()
     c := kp.Capacity()
     psum := 0
@@ -56,3 +64,52 @@ func DualGreedy(kp KnapsackProblem) ([]int,int) {
     }
     return x,psum
 }
+
+// Extended greedy heuristic for the knapsack problem
+//
+// Greedy() can be arbitrarily bad: for the items (p,w) = (2,1) and (M,M) with
+// capacity M, Greedy() only packs the first item and reaches 2 instead of M.
+// Therefore we take the best of three solutions:
+//   - the solution of Greedy(),
+//   - the best single item fitting into the knapsack,
+//   - the critical item of UpperBound(), i.e. the first item not fitting
+//     into the knapsack anymore, if it fits alone.
+//
+// Guaranteed ratio: z >= z*/2 for the optimal solution value z*.
+// Let s be the critical item. Then z* <= p[0]+...+p[s-1] + p[s], the first sum
+// is a lower bound for the Greedy() solution and p[s] is a lower bound for
+// the best single item, so the larger of both is at least z*/2.
+// The ratio 1/2 is tight: (p,w) = (M+1,M+1), (M,M), (M,M) with capacity 2M.
+//
+// Precondition: Profit[i]/Weight[i] >= Profit[i-1]/Weight[i-1] for i=1,...,n-1
+//
+// We do not check the precondition here!
+// Without the precondition the solution is still feasible, but the
+// guaranteed ratio is lost.
+func ExtGreedy(kp KnapsackProblem) ([]int,int) {
+    if x,z,ok := solveFixed(kp, ExtGreedy); ok {	// fixed items: solve the reduced problem
+	return x,z
+    }
+
+    n := kp.N()
+    c := kp.Capacity()
+    x,z := Greedy(kp)			// the greedy solution
+
+    imax := -1				// the best single item
+    for i:=0 ; i<n ; i++ {
+	if kp.Weight(i) <= c && (imax < 0 || kp.Profit(i) > kp.Profit(imax)) {
+	    imax = i
+	}
+    }
+    if s := criticalItem(kp); s < n && kp.Weight(s) <= c &&
+       (imax < 0 || kp.Profit(s) > kp.Profit(imax)) {
+	imax = s			// the critical item is a single item, too
+    }
+
+    if imax >= 0 && kp.Profit(imax) > z {	// a single item is better
+	x = make([]int,n)
+	x[imax] = 1
+	z = kp.Profit(imax)
+    }
+    return x,z
+}
@@ -0,0 +1,40 @@
+package kp
+
+import (
+    "testing"
+)
+
+func TestExtGreedy(t *testing.T) {
+    for _,kp := range testProblems(t) {
+	x,z := ExtGreedy(kp)
+	checkFeasible(t, "extgreedy", kp, x, z)
+	if _,zg := Greedy(kp); z < zg {
+	    t.Errorf("%s: z = %v is worse than the greedy solution %v", kp.Name, z, zg)
+	}
+	if zopt := enumerate(kp); 2*z < zopt {
+	    t.Errorf("%s: z = %v is less than half of the optimum %v", kp.Name, z, zopt)
+	}
+
+	x,z = DualGreedy(kp)
+	checkFeasible(t, "dualgreedy", kp, x, z)
+    }
+}
+
+// The examples of the doc comment: Greedy() can be arbitrarily bad and
+// the ratio 1/2 of ExtGreedy() is tight.
+func TestExtGreedyExamples(t *testing.T) {
+    tests := []struct {
+	kp KnapsackData
+	z  int
+    }{
+	{ KnapsackData{ Name: "greedy is bad", Dim: 2, P: []int{2,100}, W: []int{1,100}, C: 100 }, 100 },
+	{ KnapsackData{ Name: "tight ratio", Dim: 3, P: []int{101,100,100}, W: []int{101,100,100}, C: 200 }, 101 },
+    }
+    for _,test := range tests {
+	x,z := ExtGreedy(test.kp)
+	checkFeasible(t, "extgreedy", test.kp, x, z)
+	if z != test.z {
+	    t.Errorf("%s: z = %v instead of %v", test.kp.Name, z, test.z)
+	}
+    }
+}
@@ -0,0 +1,109 @@
+package kp
+
+// Compute the k best solutions of a knapsack problem by Branch and Bound.
+//
+// We use the best upper bound strategy of BranchAndBound(). As the agenda is a
+// priority queue ordered by psum + ubound and the upper bound of a goal state
+// is 0, the goal states are popped in order of decreasing profit. So we simply
+// continue the search after the first goal state until we have found k of them.
+//
+// The solutions are returned in order of decreasing objective function value.
+// If the problem has less than k solutions, all solutions are returned.
+// An error is returned if the items fixed to 1 don't fit (see FixedItemsProblem).
+func KBestBab(kp KnapsackProblem, k int) ([]Solution,error) {
+    var (
+	sols   []Solution
+	state1 *stateT
+	state2 *stateT
+    )
+
+    if r, err := fixedReduction(kp); err != nil {	// fixed items don't fit
+	return nil, err
+    } else if r != nil {				// fixed items: solve the reduced problem
+	sols,_ := KBestBab(r.Problem(), k)		// no fixed items, no error
+	return r.expandAll(sols), nil
+    }
+
+    n := kp.N()					// number of items
+    agenda := []*stateT{ initialState(kp) }	// initial state of our agenda
+
+    for len(agenda) > 0 && len(sols) < k {
+	state := agenda[0]		// get the first element of the agenda (priority queue)
+	if state.nitems == n {		// goal state: next best solution found
+	    x,z := optSol(kp, state)
+	    sols = append(sols, Solution{ X: x, Z: z })
+	    last := len(agenda)-1	// remove it from the agenda
+	    agenda[0] = agenda[last]
+	    agenda = agenda[:last]
+	    reheapTop(agenda)
+	    continue
+	}
+	if state.capacity >= kp.Weight(state.nitems) {	// is X[item]=1 feasible? if yes:
+	    state1 = successor1(kp,state)	// successor for X[item] = 1
+	} else {
+	    state1 = nil
+	}
+	state2 = successor0(kp,state)		// successor for X[item] = 0
+	agenda = pqUpdate(agenda,state1,state2)		// update the agenda
+    }
+
+    return sols, nil
+}
+
+// Compute the k best solutions of a knapsack problem by Branch and Bound.
+//
+// We use the depth first strategy of BranchAndBoundHS(), but instead of the
+// actual best solution we keep the k best goal states found so far.
+// A state is only pruned if its upper bound is not larger than the profit
+// of the k-th best goal state.
+//
+// The solutions are returned in order of decreasing objective function value.
+// If the problem has less than k solutions, all solutions are returned.
+// An error is returned if the items fixed to 1 don't fit (see FixedItemsProblem).
+func KBestHS(kp KnapsackProblem, k int) ([]Solution,error) {
+    var (
+	best []*stateT				// k best goal states, decreasing psum
+    )
+
+    if r, err := fixedReduction(kp); err != nil {	// fixed items don't fit
+	return nil, err
+    } else if r != nil {				// fixed items: solve the reduced problem
+	sols,_ := KBestHS(r.Problem(), k)		// no fixed items, no error
+	return r.expandAll(sols), nil
+    }
+
+    if k <= 0 {
+	return nil, nil
+    }
+    n := kp.N()					// number of items
+    agenda := []*stateT{ initialState(kp) }	// initial state of our agenda
+
+    for len(agenda) > 0 {
+	state := agenda[len(agenda)-1]		// take the top of the stack
+	agenda = agenda[0:len(agenda)-1]	// pop
+	if state.nitems == n {			// popped state is a goal state
+	    if len(best) < k || state.psum > best[k-1].psum {
+		if len(best) == k {		// insert it into the k best states
+		    best = best[:k-1]
+		}
+		i := len(best)
+		best = append(best, state)
+		for ; i>0 && best[i-1].psum < state.psum ; i-- {
+		    best[i] = best[i-1]
+		}
+		best[i] = state
+	    }
+	} else if len(best) < k || state.phi > best[k-1].psum {	// upper bound larger
+	    agenda = append(agenda,successor0(kp,state))	// push for decision = 0
+	    if state.capacity >= kp.Weight(state.nitems) {// if residual capacity is large enough
+		agenda = append(agenda,successor1(kp,state))	// push for decision = 1
+	    }
+	}
+    }
+
+    sols := make([]Solution, len(best))
+    for i,state := range best {
+	sols[i].X, sols[i].Z = optSol(kp, state)
+    }
+    return sols, nil
+}
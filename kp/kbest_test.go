@@ -0,0 +1,60 @@
+package kp
+
+import (
+    "fmt"
+    "sort"
+    "testing"
+)
+
+// Objective function values of all feasible solutions by decreasing value.
+func enumerateValues(kp KnapsackProblem) []int {
+    var zs []int
+
+    allSolutions(kp.N(), func(x []int) {
+	if z := objective(kp, x); z >= 0 {
+	    zs = append(zs, z)
+	}
+    })
+    sort.Sort(sort.Reverse(sort.IntSlice(zs)))
+    return zs
+}
+
+func TestKBest(t *testing.T) {
+    solvers := []struct {
+	name  string
+	kbest func(KnapsackProblem, int) ([]Solution,error)
+    }{
+	{ "kbestbab", KBestBab },
+	{ "kbesths", KBestHS },
+    }
+    for _,kp := range testProblems(t) {
+	zs := enumerateValues(kp)
+	for _,s := range solvers {
+	    for _,k := range []int{ 1, 5, len(zs)+1 } {
+		sols, err := s.kbest(kp, k)
+		if err != nil {
+		    t.Fatal(err)
+		}
+		want := zs
+		if k < len(zs) {
+		    want = zs[:k]
+		}
+		if len(sols) != len(want) {
+		    t.Errorf("%s, %s, k = %v: %v solutions instead of %v", s.name, kp.Name, k, len(sols), len(want))
+		    continue
+		}
+		seen := make(map[string]bool)
+		for j,sol := range sols {
+		    checkFeasible(t, s.name, kp, sol.X, sol.Z)
+		    if sol.Z != want[j] {
+			t.Errorf("%s, %s, k = %v: solution %v has the value %v instead of %v", s.name, kp.Name, k, j, sol.Z, want[j])
+		    }
+		    if seen[fmt.Sprint(sol.X)] {
+			t.Errorf("%s, %s: %v is found twice", s.name, kp.Name, sol.X)
+		    }
+		    seen[fmt.Sprint(sol.X)] = true
+		}
+	    }
+	}
+    }
+}
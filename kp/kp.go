@@ -16,11 +16,41 @@ type KnapsackData struct {
     P       []int  `json:"profits"`		// profit values
     W       []int  `json:"weights"`		// weight values
     C       int    `json:"capacity"`		// capacity of the knapsack
-    X       []int  `json:"x,omitempty"`	// binary decision variables
+    Fix     []int  `json:"fix,omitempty"`	// fixed items: FixIn (1), FixOut (-1) or FixFree (0),
+						// respected by all solvers for the 0/1 knapsack
+						// problem and by Session (see FixedItemsProblem),
+						// the multiple-choice solvers reject them
+    Bounds  []int  `json:"bounds,omitempty"`	// maximal number of copies of each item
+						// (bounded knapsack problem), 1 if omitted
+    Classes []int  `json:"classes,omitempty"`	// class of each item (multiple-choice
+						// knapsack problem), classes are 0,...,m-1
+    Capacities []int `json:"capacities,omitempty"`	// capacities of the knapsacks
+						// (multiple knapsack problem)
+    X       []int  `json:"x,omitempty"`	// binary decision variables (number of copies
+						// for the bounded knapsack problem)
     Z       int    `json:"z,omitempty"`	// objective function value
     Xf      []float64 `json:"xf,omitempty"`
 				// decision variables for solvers that may generate fractional
                                 // values for the decision variables (e.g. LP relaxation)
+    Solutions []Solution `json:"solutions,omitempty"`	// several solutions, e.g. all optimal solutions
+    LP      *LPRelaxation `json:"lp,omitempty"`	// LP relaxation details (critical item,
+						// dual price, reduced costs)
+    Sensitivity []ProfitRange `json:"sensitivity,omitempty"`	// profit ranges of the items
+    CapCurve    []int         `json:"capcurve,omitempty"`	// optimal value for each capacity
+    Breakpoints []Breakpoint  `json:"breakpoints,omitempty"`	// breakpoints of the capacity curve
+    Nodes       int64         `json:"nodes,omitempty"`		// generated states of branch and bound
+    NodesSaved  *int64        `json:"nodessaved,omitempty"`	// states saved by the warm start
+								// (Nodes of a cold run minus Nodes),
+								// may be negative
+    Certificate *Certificate  `json:"certificate,omitempty"`	// optimality certificate of x
+    Choice      []int         `json:"choice,omitempty"`		// selected item of each class
+    Assignment  []int         `json:"assignment,omitempty"`	// knapsack of each item, -1 if not packed
+}
+
+// A solution of a knapsack problem
+type Solution struct {
+    X []int `json:"x"`		// binary decision variables
+    Z int   `json:"z"`		// objective function value
 }
 
 func (kp KnapsackData) N() int {
@@ -0,0 +1,107 @@
+package kp
+
+import (
+    "fmt"
+    "testing"
+)
+
+// Small test problems with items sorted by decreasing profit/weight.
+// They are small enough to be solved by complete enumeration.
+func testProblems(t *testing.T) []KnapsackData {
+    kps := []KnapsackData{
+	{ Name: "single item", Dim: 1, P: []int{5}, W: []int{3}, C: 3 },
+	{ Name: "nothing fits", Dim: 3, P: []int{10,9,8}, W: []int{11,12,13}, C: 10 },
+	{ Name: "everything fits", Dim: 3, P: []int{10,9,8}, W: []int{1,2,3}, C: 10 },
+	{ Name: "greedy is not optimal", Dim: 3, P: []int{6,10,12}, W: []int{1,2,3}, C: 5 },
+	{ Name: "zero capacity", Dim: 2, P: []int{4,3}, W: []int{2,2}, C: 0 },
+	{ Name: "equal ratios", Dim: 4, P: []int{6,4,4,2}, W: []int{3,2,2,1}, C: 5 },
+    }
+    for _,corr := range []string{"uncorrelated", "weakly", "strongly"} {
+	for seed:=int64(1) ; seed<=4 ; seed++ {
+	    kp, err := Generate(KnapsackGenData{ N: 14, V: 50, CorrMode: corr, R: 5, CapMode: "halfwsum", Seed: seed })
+	    if err != nil {
+		t.Fatal(err)
+	    }
+	    kp.Name = fmt.Sprintf("%s %v", corr, seed)
+	    kps = append(kps, kp)
+	}
+    }
+    return kps
+}
+
+// Objective function value of x, -1 if x is not a feasible solution.
+func objective(kp KnapsackProblem, x []int) int {
+    if len(x) != kp.N() {
+	return -1
+    }
+    z := 0
+    w := 0
+    for i,xi := range x {
+	if xi != 0 && xi != 1 {
+	    return -1
+	}
+	z += xi*kp.Profit(i)
+	w += xi*kp.Weight(i)
+    }
+    if w > kp.Capacity() {
+	return -1
+    }
+    return z
+}
+
+// Visit all binary vectors of size n (complete enumeration). The vector is
+// reused, visit must copy it to keep it.
+func allSolutions(n int, visit func(x []int)) {
+    x := make([]int,n)
+    for m:=0 ; m<1<<uint(n) ; m++ {
+	for i:=0 ; i<n ; i++ {
+	    x[i] = (m >> uint(i)) & 1
+	}
+	visit(x)
+    }
+}
+
+// Best value of all binary vectors of size n by complete enumeration.
+// value(x) is the objective function value of x, -1 if x is infeasible.
+// The result is -1 if there is no feasible x.
+func enumerateBy(n int, value func(x []int) int) int {
+    best := -1
+    allSolutions(n, func(x []int) {
+	if z := value(x); z > best {
+	    best = z
+	}
+    })
+    return best
+}
+
+// Optimal objective function value by complete enumeration of all solutions.
+func enumerate(kp KnapsackProblem) int {
+    return enumerateBy(kp.N(), func(x []int) int { return objective(kp, x) })
+}
+
+// Check that x is a feasible solution with objective function value z, where
+// value(x) is -1 for an infeasible x, and that z doesn't exceed the optimum
+// zopt (exact: z is optimal).
+func checkSolution(t *testing.T, name string, value func(x []int) int, x []int, z int, zopt int, exact bool) {
+    t.Helper()
+    if zx := value(x); zx < 0 || zx != z {
+	t.Errorf("%s: x = %v is infeasible or has not the value z = %v", name, x, z)
+    }
+    if z > zopt || (exact && z != zopt) {
+	t.Errorf("%s: z = %v, but the optimum is %v", name, z, zopt)
+    }
+}
+
+// Check that x is an optimal solution with objective function value z.
+func checkOptimal(t *testing.T, name string, kp KnapsackData, x []int, z int) {
+    t.Helper()
+    value := func(x []int) int { return objective(kp, x) }
+    checkSolution(t, name + ", " + kp.Name, value, x, z, enumerate(kp), true)
+}
+
+// Check that x is a feasible solution with objective function value z.
+func checkFeasible(t *testing.T, name string, kp KnapsackData, x []int, z int) {
+    t.Helper()
+    value := func(x []int) int { return objective(kp, x) }
+    checkSolution(t, name + ", " + kp.Name, value, x, z, z, false)
+}
@@ -0,0 +1,107 @@
+package kp
+
+// Local search improvement for a solution x of a knapsack problem.
+//
+// x may be the result of any heuristic. We apply the following moves until
+// none of them improves the solution anymore (local optimum):
+//   - add:  an item not in the knapsack is packed
+//   - 1-1:  an item in the knapsack is exchanged by a more profitable one
+//   - 2-1:  two items in the knapsack are exchanged by a more profitable one
+// Drop moves (an item is removed) never improve a solution on their own,
+// so we only use them to repair an infeasible x: starting with the last item
+// (the least profit/weight value if the items are sorted) we remove items
+// until the solution becomes feasible.
+//
+// We always apply the first improving move we find. x is not modified,
+// LocalSearch() returns the improved solution and its objective function value.
+// x must be a binary vector of the problem size, otherwise an error is returned.
+func LocalSearch(kp KnapsackProblem, x []int) ([]int,int,error) {
+    if err := checkBinary(x, kp.N(), "solution"); err != nil {
+	return nil,0,err
+    }
+    if r, err := fixedReduction(kp); err != nil {	// fixed items don't fit
+	return nil,0,err
+    } else if r != nil {			// fixed items: search on the free items
+	return solveReduced(r, func(p KnapsackProblem) ([]int,int,error) { return LocalSearch(p, r.Restrict(x)) })
+    }
+
+    n := kp.N()
+    c := kp.Capacity()
+    y := make([]int,n)
+    z := 0
+    w := 0
+    for i:=0 ; i<n ; i++ {		// copy x and compute profit and weight sum
+	if x[i] == 1 {
+	    y[i] = 1
+	    z += kp.Profit(i)
+	    w += kp.Weight(i)
+	}
+    }
+
+    for i:=n-1 ; i>=0 && w > c ; i-- {	// drop moves: repair an infeasible solution
+	if y[i] == 1 {
+	    y[i] = 0
+	    z -= kp.Profit(i)
+	    w -= kp.Weight(i)
+	}
+    }
+
+    for improved:=true ; improved ; {
+	improved = false
+
+	for j:=0 ; j<n ; j++ {			// add moves
+	    if y[j] == 0 && w+kp.Weight(j) <= c {
+		y[j] = 1
+		z += kp.Profit(j)
+		w += kp.Weight(j)
+		improved = true
+	    }
+	}
+	if improved {
+	    continue
+	}
+
+	for i:=0 ; i<n && !improved ; i++ {	// 1-1 swap moves: i out, j in
+	    if y[i] == 0 {
+		continue
+	    }
+	    for j:=0 ; j<n ; j++ {
+		if y[j] == 0 && kp.Profit(j) > kp.Profit(i) &&
+		   w-kp.Weight(i)+kp.Weight(j) <= c {
+		    y[i], y[j] = 0, 1
+		    z += kp.Profit(j) - kp.Profit(i)
+		    w += kp.Weight(j) - kp.Weight(i)
+		    improved = true
+		    break
+		}
+	    }
+	}
+	if improved {
+	    continue
+	}
+
+	for i:=0 ; i<n && !improved ; i++ {	// 2-1 swap moves: i and k out, j in
+	    if y[i] == 0 {
+		continue
+	    }
+	    for k:=i+1 ; k<n && !improved ; k++ {
+		if y[k] == 0 {
+		    continue
+		}
+		pik := kp.Profit(i) + kp.Profit(k)
+		wik := kp.Weight(i) + kp.Weight(k)
+		for j:=0 ; j<n ; j++ {
+		    if y[j] == 0 && kp.Profit(j) > pik && w-wik+kp.Weight(j) <= c {
+			y[i], y[k], y[j] = 0, 0, 1
+			z += kp.Profit(j) - pik
+			w += kp.Weight(j) - wik
+			improved = true
+			break
+		    }
+		}
+	    }
+	}
+    }
+
+    return y,z,nil
+}
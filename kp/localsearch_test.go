@@ -0,0 +1,61 @@
+package kp
+
+import (
+    "testing"
+)
+
+func TestLocalSearch(t *testing.T) {
+    for _,kp := range testProblems(t) {
+	n := kp.N()
+	empty := make([]int,n)
+	full := make([]int,n)		// infeasible unless everything fits
+	for i := range full {
+	    full[i] = 1
+	}
+	greedy,_ := Greedy(kp)
+
+	for _,x := range [][]int{ empty, full, greedy } {
+	    x0 := append([]int(nil), x...)
+	    y,z,err := LocalSearch(kp, x)
+	    if err != nil {
+		t.Fatal(err)
+	    }
+	    checkFeasible(t, "localsearch", kp, y, z)
+	    for i := range x {
+		if x[i] != x0[i] {
+		    t.Fatalf("%s: x was modified", kp.Name)
+		}
+	    }
+	    if z0 := objective(kp, x); z < z0 {
+		t.Errorf("%s: z = %v is worse than the start %v", kp.Name, z, z0)
+	    }
+
+	    w := 0				// local optimum: no add or 1-1 move
+	    for i := range y {
+		w += y[i]*kp.W[i]
+	    }
+	    for i := range y {
+		if y[i] == 1 {
+		    continue
+		}
+		if w + kp.W[i] <= kp.C {
+		    t.Errorf("%s: item %v can be added to %v", kp.Name, i, y)
+		}
+		for j := range y {
+		    if y[j] == 1 && kp.P[i] > kp.P[j] && w - kp.W[j] + kp.W[i] <= kp.C {
+			t.Errorf("%s: item %v can be exchanged by %v in %v", kp.Name, j, i, y)
+		    }
+		}
+	    }
+	}
+    }
+}
+
+func TestLocalSearchErrors(t *testing.T) {
+    kp := testProblems(t)[3]
+    for _,x := range [][]int{ nil, {1,0}, {1,2,0}, {0,-1,0} } {
+	if _,_,err := LocalSearch(kp, x); err == nil {
+	    t.Errorf("x = %v: no error", x)
+	}
+    }
+}
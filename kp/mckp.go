@@ -0,0 +1,345 @@
+package kp
+
+import (
+    "errors"
+    "sort"
+)
+
+// Multiple-choice knapsack problem: the items are partitioned into classes
+// 0,...,m-1 and exactly one item of each class has to be selected.
+// Profits may be negative, since an item of each class is selected anyway.
+type MultipleChoiceKnapsackProblem interface {
+    KnapsackProblem
+    Class(i int) int			// class of item i
+}
+
+// Class of item i.
+func (kp KnapsackData) Class(i int) int {
+    return kp.Classes[i]
+}
+
+var errMCInfeasible = errors.New("infeasible: the lightest items of the classes exceed the capacity")
+
+// Check the classes of a multiple-choice knapsack problem: every item has a
+// class in 0,...,m-1 and no class is empty. The weights must not be negative
+// (the profits may be).
+func CheckClasses(kp *KnapsackData) error {
+    if len(kp.Classes) != kp.Dim {
+	return errors.New("wrong input: classes and problem have different sizes")
+    }
+    for _,w := range kp.W {
+	if w < 0 {
+	    return errors.New("wrong input: negative weight")
+	}
+    }
+    m := 0
+    for _,k := range kp.Classes {
+	if k < 0 {
+	    return errors.New("wrong input: negative class")
+	}
+	if k >= m {
+	    m = k+1
+	}
+    }
+    count := make([]int, m)
+    for _,k := range kp.Classes {
+	count[k]++
+    }
+    for k:=0 ; k<m ; k++ {
+	if count[k] == 0 {
+	    return errors.New("wrong input: empty class")
+	}
+    }
+    return nil
+}
+
+// Items of each class, with dominated items removed and sorted by increasing
+// weight (and increasing profit).
+// Item j is dominated by item i of the same class, if Weight[i] <= Weight[j]
+// and Profit[i] >= Profit[j]: an optimal solution never needs item j.
+// Of identical items only the first one is kept.
+func MCDominance(kp MultipleChoiceKnapsackProblem) [][]int {
+    var classes [][]int
+
+    n := kp.N()
+    for i:=0 ; i<n ; i++ {
+	k := kp.Class(i)
+	for len(classes) <= k {
+	    classes = append(classes, nil)
+	}
+	classes[k] = append(classes[k], i)
+    }
+    for k,items := range classes {
+	sort.SliceStable(items, func(a, b int) bool {	// by weight, then by decreasing profit
+	    if kp.Weight(items[a]) != kp.Weight(items[b]) {
+		return kp.Weight(items[a]) < kp.Weight(items[b])
+	    }
+	    return kp.Profit(items[a]) > kp.Profit(items[b])
+	})
+	undominated := items[:0]
+	for _,i := range items {
+	    if len(undominated) == 0 || kp.Profit(i) > kp.Profit(undominated[len(undominated)-1]) {
+		undominated = append(undominated, i)
+	    }
+	}
+	classes[k] = undominated
+    }
+    return classes
+}
+
+// Items of each class, with LP-dominated items removed.
+// The remaining items of a class form the upper convex hull of the points
+// (Weight[i],Profit[i]): item j between items i and l (by weight) is
+// LP-dominated, if the slope from i to j doesn't exceed the slope from j to l.
+// LP-dominated items are never selected in the LP relaxation, but they may
+// be part of an optimal solution, so the exact solvers only use MCDominance().
+func MCLPDominance(kp MultipleChoiceKnapsackProblem) [][]int {
+    classes := MCDominance(kp)
+    for k,items := range classes {
+	hull := items[:0]
+	for _,l := range items {
+	    for len(hull) >= 2 {
+		i, j := hull[len(hull)-2], hull[len(hull)-1]
+		if (kp.Profit(j)-kp.Profit(i))*(kp.Weight(l)-kp.Weight(j)) >
+		   (kp.Profit(l)-kp.Profit(j))*(kp.Weight(j)-kp.Weight(i)) {
+		    break
+		}
+		hull = hull[:len(hull)-1]	// j is LP-dominated
+	    }
+	    hull = append(hull, l)
+	}
+	classes[k] = hull
+    }
+    return classes
+}
+
+// An incremental step from one item of the convex hull of a class to the next one.
+type mcStep struct {
+    class int
+    item  int				// item after the step
+    dp    int				// additional profit
+    dw    int				// additional weight
+}
+
+// LP relaxation of a multiple-choice knapsack problem for the classes
+// first,...,m-1 and capacity c.
+type mcLP struct {
+    hull  [][]int			// LP-undominated items of each class
+    steps []mcStep			// steps by decreasing slope dp/dw
+}
+
+func newMCLP(kp MultipleChoiceKnapsackProblem) *mcLP {
+    lp := &mcLP{ hull: MCLPDominance(kp) }
+    for k,items := range lp.hull {
+	for j:=1 ; j<len(items) ; j++ {
+	    lp.steps = append(lp.steps, mcStep{ class: k, item: items[j],
+		dp: kp.Profit(items[j]) - kp.Profit(items[j-1]),
+		dw: kp.Weight(items[j]) - kp.Weight(items[j-1]) })
+	}
+    }
+    sort.SliceStable(lp.steps, func(a, b int) bool {	// the slopes of a class decrease,
+	sa, sb := lp.steps[a], lp.steps[b]		// so their order is kept
+	return sa.dp*sb.dw > sb.dp*sa.dw
+    })
+    return lp
+}
+
+// Bound of the LP relaxation for the classes first,...,m-1 and capacity c.
+// We start with the lightest item of each class and apply the steps by
+// decreasing slope as long as they fit, the last one fractionally.
+// If x is not nil, the LP solution is stored in x.
+// The second result is false, if even the lightest items don't fit.
+func (lp *mcLP) bound(kp KnapsackProblem, first int, c int, x []float64) (int,bool) {
+    ub := 0
+    for k:=first ; k<len(lp.hull) ; k++ {
+	i := lp.hull[k][0]
+	ub += kp.Profit(i)
+	c -= kp.Weight(i)
+	if x != nil {
+	    x[i] = 1.0
+	}
+    }
+    if c < 0 {
+	return 0,false
+    }
+    var last map[int]int		// actual item of each class for x
+    if x != nil {
+	last = make(map[int]int)
+    }
+    for _,st := range lp.steps {
+	if st.class < first {
+	    continue
+	}
+	if st.dw <= c {			// the whole step fits
+	    ub += st.dp
+	    c -= st.dw
+	    if x != nil {
+		prev, ok := last[st.class]
+		if !ok {
+		    prev = lp.hull[st.class][0]
+		}
+		x[prev] = 0.0
+		x[st.item] = 1.0
+		last[st.class] = st.item
+	    }
+	    continue
+	}
+	ub += st.dp*c/st.dw		// the last step fractionally
+	if x != nil {
+	    prev, ok := last[st.class]
+	    if !ok {
+		prev = lp.hull[st.class][0]
+	    }
+	    f := float64(c)/float64(st.dw)
+	    x[prev] = 1.0-f
+	    x[st.item] = f
+	}
+	break
+    }
+    return ub,true
+}
+
+// Upper bound for a multiple-choice knapsack problem by its LP relaxation.
+//
+// After removing LP-dominated items, the LP relaxation is solved greedily:
+// start with the lightest item of each class and go over to heavier items
+// of the convex hull by decreasing slope (additional profit per additional
+// weight) until the capacity is exhausted. At most one class contains two
+// fractional items. This is the bound Dyer and Zemel compute in linear time
+// by a median search for the critical slope, we simply sort the slopes.
+// An error is returned if the problem is infeasible.
+func MCUpperBound(kp MultipleChoiceKnapsackProblem) ([]float64,int,error) {
+    if err := noFixedItems(kp); err != nil {
+	return nil,0,err
+    }
+
+    x := make([]float64, kp.N())
+    ub, ok := newMCLP(kp).bound(kp, 0, kp.Capacity(), x)
+    if !ok {
+	return nil,0,errMCInfeasible
+    }
+    return x,ub,nil
+}
+
+// Solve a multiple-choice knapsack problem with dynamic programming.
+//
+// v[k][s] is the maximal profit of the classes k,...,m-1 for the rest
+// capacity s, i.e. the maximum of Profit[i] + v[k+1][s-Weight[i]] over the
+// items i of class k (dominated items are skipped). Profits may be negative,
+// so we mark the rest capacities without a feasible selection separately.
+// An error is returned if the problem is infeasible.
+func MCDynProg(kp MultipleChoiceKnapsackProblem) ([]int,int,error) {
+    if err := noFixedItems(kp); err != nil {
+	return nil,0,err
+    }
+
+    classes := MCDominance(kp)
+    m := len(classes)
+    c := kp.Capacity()
+
+    policy := makePolicyTable(m, c)	// policy[k][s] stores the optimal item
+    vv := make([]int, c+1)		// value function for class k+1
+    ff := make([]bool, c+1)		// feasible rest capacities for class k+1
+    for s:=0 ; s<=c ; s++ {
+	ff[s] = true
+    }
+    for k:=m-1 ; k>=0 ; k-- {
+	v := make([]int, c+1)		// value function for class k
+	f := make([]bool, c+1)		// feasible rest capacities for class k
+	for s:=0 ; s<=c ; s++ {
+	    for _,i := range classes[k] {
+		if kp.Weight(i) > s {	// sorted by weight
+		    break
+		}
+		r := s - kp.Weight(i)
+		if ff[r] && (!f[s] || kp.Profit(i) + vv[r] > v[s]) {
+		    v[s] = kp.Profit(i) + vv[r]
+		    f[s] = true
+		    policy[k][s] = i
+		}
+	    }
+	}
+	vv, ff = v, f
+    }
+    if !ff[c] {
+	return nil,0,errMCInfeasible
+    }
+
+    x := make([]int, kp.N())		// forward computation
+    s := c
+    for k:=0 ; k<m ; k++ {
+	i := policy[k][s]
+	x[i] = 1
+	s -= kp.Weight(i)
+    }
+    return x,vv[c],nil
+}
+
+// Solve a multiple-choice knapsack problem by branch and bound.
+//
+// A depth first search over the classes: for each class we try its
+// undominated items by decreasing profit. A state is pruned if its profit
+// plus the LP bound of the remaining classes (see MCUpperBound()) doesn't
+// exceed the best solution value found so far.
+// An error is returned if the problem is infeasible.
+func MCBranchAndBound(kp MultipleChoiceKnapsackProblem) ([]int,int,error) {
+    var dfs func(k int, c int, psum int)
+
+    if err := noFixedItems(kp); err != nil {
+	return nil,0,err
+    }
+
+    classes := MCDominance(kp)
+    m := len(classes)
+    lp := newMCLP(kp)
+
+    x := make([]int, kp.N())
+    xb := make([]int, kp.N())		// best solution
+    zb := 0
+    found := false			// xb is valid (profits may be negative)
+    dfs = func(k int, c int, psum int) {
+	if k == m {
+	    if !found || psum > zb {
+		zb = psum
+		found = true
+		copy(xb, x)
+	    }
+	    return
+	}
+	ub, ok := lp.bound(kp, k, c, nil)
+	if !ok || (found && psum + ub <= zb) {	// prune
+	    return
+	}
+	items := classes[k]
+	for j:=len(items)-1 ; j>=0 ; j-- {	// decreasing profit
+	    i := items[j]
+	    if kp.Weight(i) > c {
+		continue
+	    }
+	    x[i] = 1
+	    dfs(k+1, c-kp.Weight(i), psum+kp.Profit(i))
+	    x[i] = 0
+	}
+    }
+    dfs(0, kp.Capacity(), 0)
+
+    if !found {
+	return nil,0,errMCInfeasible
+    }
+    return xb,zb,nil
+}
+
+// Selected item of each class of a solution x.
+func MCChoice(kp MultipleChoiceKnapsackProblem, x []int) []int {
+    var choice []int
+    for i,xi := range x {
+	k := kp.Class(i)
+	for len(choice) <= k {
+	    choice = append(choice, -1)
+	}
+	if xi == 1 {
+	    choice[k] = i
+	}
+    }
+    return choice
+}
@@ -0,0 +1,144 @@
+package kp
+
+import (
+    "math/rand"
+    "testing"
+)
+
+// Optimal objective function value of a multiple-choice knapsack problem with
+// m classes by complete enumeration, false if it is infeasible.
+func enumerateMC(kp KnapsackData, m int) (int,bool) {
+    var enum func(k int, c int, z int)
+
+    best, found := 0, false
+    enum = func(k int, c int, z int) {	// select an item of class k
+	if k == m {
+	    if !found || z > best {
+		best, found = z, true
+	    }
+	    return
+	}
+	for i:=0 ; i<kp.Dim ; i++ {
+	    if kp.Classes[i] == k && kp.W[i] <= c {
+		enum(k+1, c-kp.W[i], z+kp.P[i])
+	    }
+	}
+    }
+    enum(0, kp.C, 0)
+    return best, found
+}
+
+// Objective function value of x and true, if x selects exactly one item of
+// each of the m classes and fits into the knapsack.
+func objectiveMC(kp KnapsackData, m int, x []int) (int,bool) {
+    if len(x) != kp.Dim {
+	return 0,false
+    }
+    count := make([]int, m)
+    z, w := 0, 0
+    for i,xi := range x {
+	if xi != 0 && xi != 1 {
+	    return 0,false
+	}
+	count[kp.Classes[i]] += xi
+	z += xi*kp.P[i]
+	w += xi*kp.W[i]
+    }
+    for _,c := range count {
+	if c != 1 {
+	    return 0,false
+	}
+    }
+    return z, w <= kp.C
+}
+
+func TestMultipleChoice(t *testing.T) {
+    kps := []KnapsackData{
+	{ Name: "one class", Dim: 3, P: []int{5,8,9}, W: []int{2,4,7}, C: 6, Classes: []int{0,0,0} },
+	{ Name: "infeasible", Dim: 4, P: []int{5,8,9,1}, W: []int{4,5,7,3}, C: 6, Classes: []int{0,0,1,1} },
+	{ Name: "negative profits", Dim: 4, P: []int{-5,-2,-7,-1}, W: []int{1,3,1,4}, C: 5, Classes: []int{0,0,1,1} },
+	{ Name: "dominated items", Dim: 5, P: []int{5,4,5,6,2}, W: []int{2,3,2,3,1}, C: 4, Classes: []int{0,0,0,1,1} },
+    }
+    r := rand.New(rand.NewSource(1))
+    for k:=0 ; k<30 ; k++ {
+	m := 1 + r.Intn(4)
+	n := m + r.Intn(10)
+	kp := KnapsackData{ Name: "random", Dim: n, P: make([]int,n), W: make([]int,n), Classes: make([]int,n) }
+	for i:=0 ; i<n ; i++ {
+	    kp.P[i] = r.Intn(40) - 10
+	    kp.W[i] = 1 + r.Intn(30)
+	    kp.Classes[i] = i			// every class gets an item
+	    if i >= m {
+		kp.Classes[i] = r.Intn(m)
+	    }
+	}
+	kp.C = r.Intn(30*m)
+	kps = append(kps, kp)
+    }
+
+    solvers := []struct {
+	name  string
+	solve func(MultipleChoiceKnapsackProblem) ([]int,int,error)
+    }{
+	{ "dp", MCDynProg },
+	{ "bab", MCBranchAndBound },
+    }
+    for _,kp := range kps {
+	if err := CheckClasses(&kp); err != nil {
+	    t.Fatal(err)
+	}
+	m := len(MCDominance(kp))
+	zopt, feasible := enumerateMC(kp, m)
+
+	_,ub,err := MCUpperBound(kp)
+	if (err == nil) != feasible || (feasible && ub < zopt) {
+	    t.Errorf("%s: upper bound %v, optimum %v, error %v", kp.Name, ub, zopt, err)
+	}
+	for _,s := range solvers {
+	    x,z,err := s.solve(kp)
+	    if !feasible {
+		if err == nil {
+		    t.Errorf("%s, %s: no error for an infeasible problem", s.name, kp.Name)
+		}
+		continue
+	    }
+	    if err != nil || z != zopt {
+		t.Errorf("%s, %s: z = %v, but the optimum is %v, error %v", s.name, kp.Name, z, zopt, err)
+		continue
+	    }
+	    if zx, ok := objectiveMC(kp, m, x); !ok || zx != z {	// exactly one item per class
+		t.Errorf("%s, %s: x = %v is infeasible or has not the value z = %v", s.name, kp.Name, x, z)
+	    }
+	    choice := MCChoice(kp, x)
+	    for k,i := range choice {
+		if i < 0 || kp.Classes[i] != k || x[i] != 1 {
+		    t.Errorf("%s, %s: no item of class %v in x = %v", s.name, kp.Name, k, x)
+		}
+	    }
+	    if len(choice) != m {
+		t.Errorf("%s, %s: %v classes in the choice %v instead of %v", s.name, kp.Name, len(choice), choice, m)
+	    }
+	}
+    }
+}
+
+func TestCheckClasses(t *testing.T) {
+    tests := []struct {
+	classes []int
+	w       []int
+	ok      bool
+    }{
+	{ []int{0,1,0}, []int{1,2,3}, true },
+	{ []int{0,0}, []int{1,2,3}, false },		// wrong size
+	{ []int{0,-1,0}, []int{1,2,3}, false },		// negative class
+	{ []int{0,2,0}, []int{1,2,3}, false },		// class 1 is empty
+	{ []int{0,1,0}, []int{1,-2,3}, false },		// negative weight
+	{ []int{0,1,0}, []int{0,2,3}, true },		// zero weight
+    }
+    for _,test := range tests {
+	kp := KnapsackData{ Dim: 3, P: []int{1,-2,3}, W: test.w, C: 3, Classes: test.classes }
+	if err := CheckClasses(&kp); (err == nil) != test.ok {
+	    t.Errorf("classes %v, weights %v: %v", test.classes, test.w, err)
+	}
+    }
+}
@@ -0,0 +1,350 @@
+package kp
+
+import (
+    "errors"
+    "math"
+    "sort"
+)
+
+// Multidimensional knapsack problem: every item consumes several resources
+// (e.g. weight, volume and money) and the knapsack has a capacity for each
+// resource (dimension).
+type MultidimensionalKnapsackProblem interface {
+    N()                  int		// number of items, 0,...,n-1
+    Dimensions()         int		// number of resources, 0,...,D-1
+    Profit(i int)        int		// profit of item i
+    Weight(i int, d int) int		// weight of item i in dimension d
+    Capacity(d int)      int		// capacity of dimension d
+}
+
+// Multidimensional knapsack problem data
+type MDKnapsackData struct {
+    Name    string    `json:"name,omitempty"`	// problem name, optional
+    Comment string    `json:"comment,omitempty"`	// comment, optional
+    Type    string    `json:"type"`		// problem type, unused at the moment
+    Dim     int       `json:"dimension"`		// problem size
+    P       []int     `json:"profits"`		// profit values
+    W       [][]int   `json:"weights"`		// weight matrix, W[d][i] is the weight
+						// of item i in dimension d
+    C       []int     `json:"capacities"`		// capacity of each dimension
+    X       []int     `json:"x,omitempty"`	// binary decision variables
+    Z       int       `json:"z,omitempty"`	// objective function value
+    Xf      []float64 `json:"xf,omitempty"`	// fractional solution of the surrogate relaxation
+}
+
+func (kp MDKnapsackData) N() int {
+    return kp.Dim
+}
+
+func (kp MDKnapsackData) Dimensions() int {
+    return len(kp.C)
+}
+
+func (kp MDKnapsackData) Profit(i int) int {
+    return kp.P[i]
+}
+
+func (kp MDKnapsackData) Weight(i int, d int) int {
+    return kp.W[d][i]
+}
+
+func (kp MDKnapsackData) Capacity(d int) int {
+    return kp.C[d]
+}
+
+// Check the sizes of a multidimensional knapsack problem.
+func CheckMDData(kp *MDKnapsackData) error {
+    if len(kp.P) != kp.Dim {
+	return errors.New("wrong input: profits and problem have different sizes")
+    }
+    if len(kp.W) != len(kp.C) {
+	return errors.New("wrong input: weights and capacities have different dimensions")
+    }
+    for d,w := range kp.W {
+	if len(w) != kp.Dim {
+	    return errors.New("wrong input: weights and problem have different sizes")
+	}
+	if kp.C[d] < 0 {
+	    return errors.New("wrong input: negative capacity")
+	}
+	for _,wi := range w {
+	    if wi < 0 {
+		return errors.New("wrong input: negative weight")
+	    }
+	}
+    }
+    return nil
+}
+
+// Surrogate relaxation of a multidimensional knapsack problem.
+//
+// With multipliers u[d] >= 0 the constraints are aggregated into the single
+// constraint sum_d u[d]*Weight(i,d)*X[i] <= sum_d u[d]*Capacity(d).
+// Every solution of the problem satisfies this constraint, so the LP bound
+// of the resulting knapsack problem is an upper bound for any u.
+type mdSurrogate struct {
+    u     []float64			// multipliers
+    sw    []float64			// surrogate weight of each item
+    order []int				// items by decreasing profit/surrogate weight
+}
+
+func newMDSurrogate(kp MultidimensionalKnapsackProblem, u []float64) *mdSurrogate {
+    n := kp.N()
+    s := &mdSurrogate{ u: u, sw: make([]float64,n), order: make([]int,n) }
+    for i:=0 ; i<n ; i++ {
+	for d:=0 ; d<kp.Dimensions() ; d++ {
+	    s.sw[i] += u[d]*float64(kp.Weight(i,d))
+	}
+	s.order[i] = i
+    }
+    sort.SliceStable(s.order, func(a, b int) bool {	// cross multiplication: sw may be 0
+	i, j := s.order[a], s.order[b]
+	return float64(kp.Profit(i))*s.sw[j] > float64(kp.Profit(j))*s.sw[i]
+    })
+    return s
+}
+
+// LP bound of the surrogate relaxation for the items order[first],... and the
+// residual capacities res. Items which don't fit into res are skipped.
+// If x is not nil, the fractional solution is stored in x.
+// The bound is psum + frac: psum is the (exact) profit sum of the items which
+// fit completely, frac the profit of the fractional item.
+func (s *mdSurrogate) bound(kp MultidimensionalKnapsackProblem, first int, res []int, x []float64) (int,float64) {
+    c := 0.0
+    for d,r := range res {
+	c += s.u[d]*float64(r)
+    }
+    psum := 0
+    for k:=first ; k<len(s.order) ; k++ {
+	i := s.order[k]
+	if !mdFits(kp, i, res) {
+	    continue
+	}
+	if s.sw[i] <= c {
+	    psum += kp.Profit(i)
+	    c -= s.sw[i]
+	    if x != nil {
+		x[i] = 1.0
+	    }
+	    continue
+	}
+	f := c/s.sw[i]			// the last item fractionally
+	if x != nil {
+	    x[i] = f
+	}
+	return psum, f*float64(kp.Profit(i))
+    }
+    return psum, 0.0
+}
+
+// Does item i fit into the residual capacities res?
+func mdFits(kp MultidimensionalKnapsackProblem, i int, res []int) bool {
+    for d,r := range res {
+	if kp.Weight(i,d) > r {
+	    return false
+	}
+    }
+    return true
+}
+
+// Integer bound of the bound psum + frac (see mdSurrogate.bound()). Only frac
+// has rounding errors, we tolerate a relative error of 1e-9. A fixed absolute
+// tolerance would be smaller than the float spacing for large profits.
+func mdFloor(psum int, frac float64) int {
+    return psum + int(math.Floor(frac + 1e-9*math.Max(frac, 1.0)))
+}
+
+// Surrogate relaxation with good multipliers: we start with u[d] = 1/Capacity(d)
+// and adjust the multipliers by the relative load of the dimensions in the
+// fractional solution (a subgradient like method). The relaxation with the
+// smallest bound is returned.
+func mdBestSurrogate(kp MultidimensionalKnapsackProblem) *mdSurrogate {
+    const iterations = 50
+
+    n := kp.N()
+    dims := kp.Dimensions()
+    res := make([]int,dims)
+    u := make([]float64,dims)
+    for d:=0 ; d<dims ; d++ {
+	res[d] = kp.Capacity(d)
+	u[d] = 1.0 / math.Max(float64(res[d]), 1.0)
+    }
+
+    var best *mdSurrogate
+    bestUB := math.Inf(1)
+    for it:=0 ; it<iterations ; it++ {
+	s := newMDSurrogate(kp, u)
+	x := make([]float64,n)
+	psum, frac := s.bound(kp, 0, res, x)
+	if ub := float64(psum) + frac; ub < bestUB {
+	    best, bestUB = s, ub
+	}
+	step := 1.0 / float64(it+1)
+	next := make([]float64,dims)
+	for d:=0 ; d<dims ; d++ {	// overloaded dimensions get larger multipliers
+	    load := 0.0
+	    for i:=0 ; i<n ; i++ {
+		load += x[i]*float64(kp.Weight(i,d))
+	    }
+	    g := load/math.Max(float64(res[d]), 1.0) - 1.0
+	    next[d] = u[d] * math.Exp(step*g)
+	}
+	u = next
+    }
+    return best
+}
+
+// Upper bound for a multidimensional knapsack problem by the surrogate
+// relaxation (see mdBestSurrogate()). The first result is the fractional
+// solution of the relaxation.
+func MDUpperBound(kp MultidimensionalKnapsackProblem) ([]float64,int) {
+    res := make([]int, kp.Dimensions())
+    for d:=range res {
+	res[d] = kp.Capacity(d)
+    }
+    x := make([]float64, kp.N())
+    s := mdBestSurrogate(kp)
+    return x, mdFloor(s.bound(kp, 0, res, x))
+}
+
+// Solve a multidimensional knapsack problem by a primal-dual heuristic.
+//
+// Dual phase (Senju and Toyoda): we start with all items selected and remove
+// items until the solution is feasible. The penalty of an item is its weight
+// in the overloaded dimensions (relative to the capacity, weighted by the
+// overload). The item with the smallest profit per penalty is removed.
+// Primal phase: the removed items are added again by decreasing profit per
+// relative weight (sum_d Weight(i,d)/Capacity(d)) as long as they fit.
+func MDPrimalDual(kp MultidimensionalKnapsackProblem) ([]int,int) {
+    n := kp.N()
+    dims := kp.Dimensions()
+    x := make([]int,n)
+    load := make([]int,dims)
+    for i:=0 ; i<n ; i++ {
+	x[i] = 1
+	for d:=0 ; d<dims ; d++ {
+	    load[d] += kp.Weight(i,d)
+	}
+    }
+    capacity := func(d int) float64 {
+	return math.Max(float64(kp.Capacity(d)), 1.0)
+    }
+
+    for {				// dual phase
+	excess := make([]float64,dims)
+	feasible := true
+	for d:=0 ; d<dims ; d++ {
+	    if load[d] > kp.Capacity(d) {
+		excess[d] = float64(load[d]-kp.Capacity(d)) / capacity(d)
+		feasible = false
+	    }
+	}
+	if feasible {
+	    break
+	}
+	r := -1				// item to remove
+	rval := 0.0
+	for i:=0 ; i<n ; i++ {
+	    if x[i] == 0 {
+		continue
+	    }
+	    penalty := 0.0
+	    for d:=0 ; d<dims ; d++ {
+		penalty += excess[d]*float64(kp.Weight(i,d))/capacity(d)
+	    }
+	    if penalty <= 0 {
+		continue
+	    }
+	    if val := float64(kp.Profit(i))/penalty; r < 0 || val < rval {
+		r, rval = i, val
+	    }
+	}
+	x[r] = 0
+	for d:=0 ; d<dims ; d++ {
+	    load[d] -= kp.Weight(r,d)
+	}
+    }
+
+    var removed []int			// primal phase
+    rel := make([]float64,n)		// relative weight of each item
+    for i:=0 ; i<n ; i++ {
+	for d:=0 ; d<dims ; d++ {
+	    rel[i] += float64(kp.Weight(i,d))/capacity(d)
+	}
+	if x[i] == 0 {
+	    removed = append(removed, i)
+	}
+    }
+    sort.SliceStable(removed, func(a, b int) bool {
+	i, j := removed[a], removed[b]
+	return float64(kp.Profit(i))*rel[j] > float64(kp.Profit(j))*rel[i]
+    })
+    res := make([]int,dims)
+    for _,i := range removed {
+	for d:=0 ; d<dims ; d++ {
+	    res[d] = kp.Capacity(d) - load[d]
+	}
+	if mdFits(kp, i, res) {
+	    x[i] = 1
+	    for d:=0 ; d<dims ; d++ {
+		load[d] += kp.Weight(i,d)
+	    }
+	}
+    }
+
+    z := 0
+    for i:=0 ; i<n ; i++ {
+	z += x[i]*kp.Profit(i)
+    }
+    return x,z
+}
+
+// Solve a multidimensional knapsack problem by branch and bound.
+//
+// A depth first search branches on the items in the order of the surrogate
+// relaxation with the best multipliers found (see mdBestSurrogate()), X[i]=1
+// first. A state is pruned, if its profit plus the LP bound of the surrogate
+// relaxation for the remaining items and residual capacities doesn't exceed
+// the best solution. The solution of MDPrimalDual() is the initial incumbent.
+func MDBranchAndBound(kp MultidimensionalKnapsackProblem) ([]int,int) {
+    var dfs func(k int, psum int)
+
+    n := kp.N()
+    dims := kp.Dimensions()
+    s := mdBestSurrogate(kp)
+    xb, zb := MDPrimalDual(kp)		// best solution
+    x := make([]int,n)
+    res := make([]int,dims)
+    for d:=0 ; d<dims ; d++ {
+	res[d] = kp.Capacity(d)
+    }
+
+    dfs = func(k int, psum int) {
+	if k == n {
+	    if psum > zb {
+		zb = psum
+		copy(xb, x)
+	    }
+	    return
+	}
+	if psum + mdFloor(s.bound(kp, k, res, nil)) <= zb {	// prune
+	    return
+	}
+	i := s.order[k]
+	if mdFits(kp, i, res) {		// X[i] = 1
+	    for d:=0 ; d<dims ; d++ {
+		res[d] -= kp.Weight(i,d)
+	    }
+	    x[i] = 1
+	    dfs(k+1, psum+kp.Profit(i))
+	    x[i] = 0
+	    for d:=0 ; d<dims ; d++ {
+		res[d] += kp.Weight(i,d)
+	    }
+	}
+	dfs(k+1, psum)			// X[i] = 0
+    }
+    dfs(0, 0)
+
+    return xb,zb
+}
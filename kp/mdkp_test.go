@@ -0,0 +1,114 @@
+package kp
+
+import (
+    "math/rand"
+    "testing"
+)
+
+// Objective function value of x, -1 if x is not a feasible solution of the
+// multidimensional knapsack problem.
+func objectiveMD(kp MDKnapsackData, x []int) int {
+    if len(x) != kp.N() {
+	return -1
+    }
+    z := 0
+    for i,xi := range x {
+	if xi != 0 && xi != 1 {
+	    return -1
+	}
+	z += xi*kp.P[i]
+    }
+    for d,c := range kp.C {
+	w := 0
+	for i,xi := range x {
+	    w += xi*kp.W[d][i]
+	}
+	if w > c {
+	    return -1
+	}
+    }
+    return z
+}
+
+func TestMultidimensional(t *testing.T) {
+    var kps []MDKnapsackData
+
+    for _,kp := range testProblems(t) {	// one dimension: 0/1 knapsack problem
+	kps = append(kps, MDKnapsackData{ Name: kp.Name, Dim: kp.Dim, P: kp.P, W: [][]int{ kp.W }, C: []int{ kp.C } })
+    }
+    r := rand.New(rand.NewSource(1))
+    for k:=0 ; k<30 ; k++ {
+	n := 1 + r.Intn(12)
+	dims := 1 + r.Intn(3)
+	kp := MDKnapsackData{ Name: "random", Dim: n, P: make([]int,n), W: make([][]int,dims), C: make([]int,dims) }
+	for i := range kp.P {
+	    kp.P[i] = 1 + r.Intn(50)
+	}
+	for d := range kp.W {
+	    kp.W[d] = make([]int,n)
+	    wsum := 0
+	    for i := range kp.W[d] {
+		kp.W[d][i] = r.Intn(30)
+		wsum += kp.W[d][i]
+	    }
+	    kp.C[d] = r.Intn(wsum+1)
+	}
+	kps = append(kps, kp)
+    }
+    for k:=0 ; k<30 ; k++ {		// profits around 1e12: the float bound
+	kp := kps[len(kps)-30+k]	// has rounding errors > 1e-9
+	kp.Name = "large profits"
+	kp.P = make([]int, kp.Dim)
+	for i := range kp.P {
+	    kp.P[i] = 1000000000000 + r.Intn(50)
+	}
+	kps = append(kps, kp)
+    }
+    kps = append(kps,
+	MDKnapsackData{ Name: "large profits, integral bound", Dim: 7,
+			P: []int{1000000000007,999999999988,999999999980,999999999980,1000000000015,999999999991,999999999996},
+			W: [][]int{ {10,21,9,6,6,20,4} }, C: []int{10} },
+	MDKnapsackData{ Name: "large profits, 5 items", Dim: 5,
+			P: []int{1000000000004,1000000000009,1000000000002,999999999976,1000000000010},
+			W: [][]int{ {20,22,28,21,16} }, C: []int{107} },
+	MDKnapsackData{ Name: "large profits, 2^40", Dim: 6,
+			P: []int{1099511627760,1099511627797,1099511627774,1099511627780,1099511627783,1099511627785},
+			W: [][]int{ {7,24,10,29,14,19} }, C: []int{50} })
+
+    for _,kp := range kps {
+	if err := CheckMDData(&kp); err != nil {
+	    t.Fatal(err)
+	}
+	value := func(x []int) int { return objectiveMD(kp, x) }
+	zopt := enumerateBy(kp.N(), value)
+	if _,ub := MDUpperBound(kp); ub < zopt {
+	    t.Errorf("%s: upper bound %v is less than the optimum %v", kp.Name, ub, zopt)
+	}
+
+	x,z := MDPrimalDual(kp)
+	checkSolution(t, "primal-dual, " + kp.Name, value, x, z, zopt, false)
+
+	x,z = MDBranchAndBound(kp)
+	checkSolution(t, "bab, " + kp.Name, value, x, z, zopt, true)
+    }
+}
+
+func TestCheckMDData(t *testing.T) {
+    tests := []struct {
+	name string
+	kp   MDKnapsackData
+	ok   bool
+    }{
+	{ "valid", MDKnapsackData{ Dim: 2, P: []int{1,2}, W: [][]int{ {1,2}, {3,4} }, C: []int{3,5} }, true },
+	{ "profits", MDKnapsackData{ Dim: 2, P: []int{1}, W: [][]int{ {1,2} }, C: []int{3} }, false },
+	{ "dimensions", MDKnapsackData{ Dim: 2, P: []int{1,2}, W: [][]int{ {1,2} }, C: []int{3,5} }, false },
+	{ "weights", MDKnapsackData{ Dim: 2, P: []int{1,2}, W: [][]int{ {1} }, C: []int{3} }, false },
+	{ "negative capacity", MDKnapsackData{ Dim: 2, P: []int{1,2}, W: [][]int{ {1,2} }, C: []int{-1} }, false },
+	{ "negative weight", MDKnapsackData{ Dim: 2, P: []int{1,2}, W: [][]int{ {1,-2} }, C: []int{3} }, false },
+    }
+    for _,test := range tests {
+	if err := CheckMDData(&test.kp); (err == nil) != test.ok {
+	    t.Errorf("%s: %v", test.name, err)
+	}
+    }
+}
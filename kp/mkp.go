@@ -0,0 +1,304 @@
+package kp
+
+import (
+    "errors"
+    "sort"
+)
+
+// Multiple knapsack problem: the items are packed into m knapsacks with
+// different capacities, every item into at most one knapsack.
+// A solution is an assignment a: a[i] is the knapsack of item i,
+// -1 if item i is not packed.
+type MultipleKnapsackProblem interface {
+    N()                     int		// number of items, 0,...,n-1
+    Profit(i int)           int		// profit of item i
+    Weight(i int)           int		// weight of item i
+    Knapsacks()             int		// number of knapsacks, 0,...,m-1
+    KnapsackCapacity(j int) int		// capacity of knapsack j
+}
+
+func (kp KnapsackData) Knapsacks() int {
+    return len(kp.Capacities)
+}
+
+func (kp KnapsackData) KnapsackCapacity(j int) int {
+    return kp.Capacities[j]
+}
+
+// Check the capacities of a multiple knapsack problem.
+func CheckCapacities(kp *KnapsackData) error {
+    if len(kp.Capacities) == 0 {
+	return errors.New("wrong input: no capacities")
+    }
+    for _,c := range kp.Capacities {
+	if c < 0 {
+	    return errors.New("wrong input: negative capacity")
+	}
+    }
+    return nil
+}
+
+// The items of a multiple knapsack problem in one knapsack with the total
+// capacity of all knapsacks (surrogate relaxation).
+type surrogateKP struct {
+    kp MultipleKnapsackProblem
+}
+
+func (s surrogateKP) N() int {
+    return s.kp.N()
+}
+
+func (s surrogateKP) Capacity() int {
+    c := 0
+    for j:=0 ; j<s.kp.Knapsacks() ; j++ {
+	c += s.kp.KnapsackCapacity(j)
+    }
+    return c
+}
+
+func (s surrogateKP) Profit(i int) int {
+    return s.kp.Profit(i)
+}
+
+func (s surrogateKP) Weight(i int) int {
+    return s.kp.Weight(i)
+}
+
+// Profit and weight of the packed items of an assignment.
+func mkpValue(kp MultipleKnapsackProblem, a []int) int {
+    z := 0
+    for i,j := range a {
+	if j >= 0 {
+	    z += kp.Profit(i)
+	}
+    }
+    return z
+}
+
+// Upper bound for a multiple knapsack problem by the surrogate relaxation:
+// the optimal value of the 0/1 knapsack problem with the total capacity of
+// all knapsacks (items which fit into no knapsack are removed).
+//
+// Precondition: Profit[i]/Weight[i] >= Profit[i-1]/Weight[i-1] for i=1,...,n-1
+//
+// We do not check the precondition here!
+// Use kp.CheckSortedItems() to check the precondition.
+func MKPUpperBound(kp MultipleKnapsackProblem) (int,error) {
+    var items []int
+
+    if err := noFixedItems(kp); err != nil {
+	return 0,err
+    }
+
+    cmax := 0
+    for j:=0 ; j<kp.Knapsacks() ; j++ {
+	if kp.KnapsackCapacity(j) > cmax {
+	    cmax = kp.KnapsackCapacity(j)
+	}
+    }
+    for i:=0 ; i<kp.N() ; i++ {
+	if kp.Weight(i) <= cmax {
+	    items = append(items, i)
+	}
+    }
+    s := surrogateKP{ kp }
+    _,z := BranchAndBoundHS(itemView{ kp: s, items: items, c: s.Capacity() })
+    return z,nil
+}
+
+// Solve a multiple knapsack problem by a greedy heuristic.
+//
+// The items are packed by decreasing ratio, each into the knapsack with the
+// smallest residual capacity it fits into (best fit). Afterwards we try to
+// exchange a packed item with a more profitable unpacked item which fits
+// into the residual capacity of its knapsack, and to pack further items.
+//
+// Precondition: Profit[i]/Weight[i] >= Profit[i-1]/Weight[i-1] for i=1,...,n-1
+//
+// We do not check the precondition here!
+func MKPGreedy(kp MultipleKnapsackProblem) ([]int,int,error) {
+    if err := noFixedItems(kp); err != nil {
+	return nil,0,err
+    }
+
+    n := kp.N()
+    m := kp.Knapsacks()
+    a := make([]int,n)
+    res := make([]int,m)		// residual capacities
+    for j:=0 ; j<m ; j++ {
+	res[j] = kp.KnapsackCapacity(j)
+    }
+    bestFit := func(i int) {		// pack item i into the best fitting knapsack
+	a[i] = -1
+	for j:=0 ; j<m ; j++ {
+	    if kp.Weight(i) <= res[j] && (a[i] < 0 || res[j] < res[a[i]]) {
+		a[i] = j
+	    }
+	}
+	if a[i] >= 0 {
+	    res[a[i]] -= kp.Weight(i)
+	}
+    }
+    for i:=0 ; i<n ; i++ {
+	bestFit(i)
+    }
+
+    for improved := true ; improved ; {	// exchange packed and unpacked items
+	improved = false
+	for k:=0 ; k<n ; k++ {
+	    j := a[k]
+	    if j < 0 {
+		continue
+	    }
+	    u := -1			// most profitable unpacked item for k
+	    for i:=0 ; i<n ; i++ {
+		if a[i] < 0 && kp.Profit(i) > kp.Profit(k) &&
+		   kp.Weight(i) - kp.Weight(k) <= res[j] && (u < 0 || kp.Profit(i) > kp.Profit(u)) {
+		    u = i
+		}
+	    }
+	    if u >= 0 {
+		res[j] += kp.Weight(k) - kp.Weight(u)
+		a[k], a[u] = -1, j
+		improved = true
+	    }
+	}
+	for i:=0 ; i<n ; i++ {		// pack further items
+	    if a[i] < 0 {
+		bestFit(i)
+	    }
+	}
+    }
+
+    return a, mkpValue(kp, a), nil
+}
+
+// Solve a multiple knapsack problem by bound and bound (in the style of the
+// algorithm MTM by Martello and Toth).
+//
+// A depth first search assigns the items by decreasing ratio to a knapsack
+// or to no knapsack. In every state we compute
+//   - an upper bound: the surrogate relaxation of the unassigned items with
+//     the total residual capacity (see MKPUpperBound()), and
+//   - a lower bound: a feasible solution which fills the knapsacks one after
+//     the other (by increasing residual capacity) optimally with the
+//     unassigned items.
+// Both subproblems are 0/1 knapsack problems, which we solve by
+// BranchAndBoundHS(). A state is pruned, if the upper bound doesn't exceed
+// the best solution, and it is solved, if the lower bound reaches the upper
+// bound. We branch along the lower bound solution: the knapsack it assigns
+// the next item to is tried first. Knapsacks with equal residual capacities
+// are interchangeable, so only one of them is tried.
+//
+// Precondition: Profit[i]/Weight[i] >= Profit[i-1]/Weight[i-1] for i=1,...,n-1
+//
+// We do not check the precondition here!
+// Use kp.CheckSortedItems() to check the precondition.
+func MKPBoundAndBound(kp MultipleKnapsackProblem) ([]int,int,error) {
+    var dfs func(i int, psum int)
+
+    if err := noFixedItems(kp); err != nil {
+	return nil,0,err
+    }
+
+    n := kp.N()
+    m := kp.Knapsacks()
+    s := surrogateKP{ kp }
+    a := make([]int,n)			// actual assignment
+    res := make([]int,m)		// residual capacities
+    for j:=0 ; j<m ; j++ {
+	res[j] = kp.KnapsackCapacity(j)
+    }
+    ab, zb, _ := MKPGreedy(kp)		// best solution (no fixed items, no error)
+
+    order := make([]int,m)		// knapsacks by increasing residual capacity
+    lower := func(i int) ([]int,int) {	// fill the knapsacks with the items i,...,n-1
+	la := make([]int,n)
+	for k:=0 ; k<n ; k++ {
+	    la[k] = -1
+	}
+	for j:=0 ; j<m ; j++ {
+	    order[j] = j
+	}
+	sort.SliceStable(order, func(x, y int) bool { return res[order[x]] < res[order[y]] })
+	free := make([]int,0,n-i)
+	for k:=i ; k<n ; k++ {
+	    free = append(free, k)
+	}
+	z := 0
+	for _,j := range order {
+	    y,zj := BranchAndBoundHS(itemView{ kp: s, items: free, c: res[j] })
+	    z += zj
+	    rest := free[:0]
+	    for k,item := range free {
+		if y[k] == 1 {
+		    la[item] = j
+		} else {
+		    rest = append(rest, item)
+		}
+	    }
+	    free = rest
+	}
+	return la,z
+    }
+    upper := func(i int) int {		// surrogate relaxation of the items i,...,n-1
+	c, cmax := 0, 0
+	for j:=0 ; j<m ; j++ {
+	    c += res[j]
+	    if res[j] > cmax {
+		cmax = res[j]
+	    }
+	}
+	var items []int
+	for k:=i ; k<n ; k++ {
+	    if kp.Weight(k) <= cmax {
+		items = append(items, k)
+	    }
+	}
+	_,z := BranchAndBoundHS(itemView{ kp: s, items: items, c: c })
+	return z
+    }
+
+    dfs = func(i int, psum int) {
+	u := upper(i)
+	if psum + u <= zb {		// prune
+	    return
+	}
+	la,l := lower(i)
+	if psum + l > zb {		// new best solution
+	    zb = psum + l
+	    copy(ab, a)
+	    for k:=i ; k<n ; k++ {
+		ab[k] = la[k]
+	    }
+	}
+	if l == u {			// solved
+	    return
+	}
+
+	w := kp.Weight(i)		// branch on item i, l < u implies i < n
+	tried := make(map[int]bool)	// residual capacities tried
+	try := func(j int) {
+	    if j < 0 || w > res[j] || tried[res[j]] {
+		return
+	    }
+	    tried[res[j]] = true
+	    res[j] -= w
+	    a[i] = j
+	    dfs(i+1, psum+kp.Profit(i))
+	    res[j] += w
+	}
+	try(la[i])			// first the knapsack of the lower bound
+	for j:=0 ; j<m ; j++ {
+	    try(j)
+	}
+	a[i] = -1			// item i is not packed
+	dfs(i+1, psum)
+    }
+    for i:=0 ; i<n ; i++ {
+	a[i] = -1
+    }
+    dfs(0, 0)
+
+    return ab,zb,nil
+}
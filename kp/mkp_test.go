@@ -0,0 +1,98 @@
+package kp
+
+import (
+    "testing"
+)
+
+// Optimal objective function value of a multiple knapsack problem by
+// complete enumeration of all assignments.
+func enumerateMKP(kp KnapsackData) int {
+    var enum func(i int) int
+
+    res := append([]int(nil), kp.Capacities...)	// residual capacities
+    enum = func(i int) int {		// best profit of the items i,...,n-1
+	if i == kp.Dim {
+	    return 0
+	}
+	best := enum(i+1)		// item i is not packed
+	for j := range res {
+	    if kp.W[i] <= res[j] {
+		res[j] -= kp.W[i]
+		if z := kp.P[i] + enum(i+1); z > best {
+		    best = z
+		}
+		res[j] += kp.W[i]
+	    }
+	}
+	return best
+    }
+    return enum(0)
+}
+
+// Check that the assignment a is feasible with objective function value z.
+func checkAssignment(t *testing.T, name string, kp KnapsackData, a []int, z int) {
+    t.Helper()
+    res := append([]int(nil), kp.Capacities...)
+    p := 0
+    for i,j := range a {
+	if j < -1 || j >= len(res) {
+	    t.Errorf("%s, %s: item %v is assigned to knapsack %v", name, kp.Name, i, j)
+	    return
+	}
+	if j >= 0 {
+	    res[j] -= kp.W[i]
+	    p += kp.P[i]
+	}
+    }
+    for j,r := range res {
+	if r < 0 {
+	    t.Errorf("%s, %s: knapsack %v overflows in %v", name, kp.Name, j, a)
+	}
+    }
+    if len(a) != kp.Dim || p != z {
+	t.Errorf("%s, %s: a = %v has not the value z = %v", name, kp.Name, a, z)
+    }
+}
+
+func TestMultipleKnapsack(t *testing.T) {
+    var kps []KnapsackData
+
+    for _,kp := range testProblems(t) {
+	if kp.Dim > 7 {
+	    kp.Dim = 7
+	    kp.P, kp.W = kp.P[:7], kp.W[:7]
+	}
+	kp.Capacities = []int{ kp.C/2, kp.C/3 }
+	kps = append(kps, kp)
+	kp.Capacities = []int{ kp.C/3, kp.C/3, kp.C/4 }
+	kps = append(kps, kp)
+    }
+
+    for _,kp := range kps {
+	if err := CheckCapacities(&kp); err != nil {
+	    t.Fatal(err)
+	}
+	zopt := enumerateMKP(kp)
+	if ub,err := MKPUpperBound(kp); err != nil || ub < zopt {
+	    t.Errorf("%s, %v: upper bound %v, error %v, but the optimum is %v", kp.Name, kp.Capacities, ub, err, zopt)
+	}
+
+	a,z,err := MKPGreedy(kp)
+	if err != nil {
+	    t.Fatal(err)
+	}
+	checkAssignment(t, "greedy", kp, a, z)
+	if z > zopt {
+	    t.Errorf("%s, %v: greedy z = %v exceeds the optimum %v", kp.Name, kp.Capacities, z, zopt)
+	}
+
+	a,z,err = MKPBoundAndBound(kp)
+	if err != nil {
+	    t.Fatal(err)
+	}
+	checkAssignment(t, "bab", kp, a, z)
+	if z != zopt {
+	    t.Errorf("%s, %v: z = %v, but the optimum is %v", kp.Name, kp.Capacities, z, zopt)
+	}
+    }
+}
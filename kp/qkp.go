@@ -0,0 +1,343 @@
+package kp
+
+import (
+    "errors"
+    "sort"
+)
+
+// Quadratic knapsack problem: in addition to the profits of the items, a pair
+// of items yields an additional profit if both items are selected.
+// The objective function is the sum of Profit[i]*X[i] over all items plus the
+// sum of P*X[I]*X[J] over all pairs.
+type QuadraticKnapsackProblem interface {
+    KnapsackProblem
+    Pairs() []PairProfit		// additional profits of item pairs
+}
+
+// Additional profit of a pair of items (sparse representation of the
+// symmetric profit matrix, pairs without additional profit are omitted).
+type PairProfit struct {
+    I int `json:"i"`			// first item
+    J int `json:"j"`			// second item
+    P int `json:"p"`			// additional profit if I and J are selected
+}
+
+// Quadratic knapsack problem data
+type QKnapsackData struct {
+    Name    string       `json:"name,omitempty"`	// problem name, optional
+    Comment string       `json:"comment,omitempty"`	// comment, optional
+    Type    string       `json:"type"`		// problem type, unused at the moment
+    Dim     int          `json:"dimension"`		// problem size
+    P       []int        `json:"profits"`		// profit values
+    W       []int        `json:"weights"`		// weight values
+    C       int          `json:"capacity"`		// capacity of the knapsack
+    PP      []PairProfit `json:"pairs,omitempty"`	// additional profits of item pairs
+    X       []int        `json:"x,omitempty"`		// binary decision variables
+    Z       int          `json:"z,omitempty"`		// objective function value
+}
+
+func (kp QKnapsackData) N() int {
+    return kp.Dim
+}
+
+func (kp QKnapsackData) Capacity() int {
+    return kp.C
+}
+
+func (kp QKnapsackData) Profit(i int) int {
+    return kp.P[i]
+}
+
+func (kp QKnapsackData) Weight(i int) int {
+    return kp.W[i]
+}
+
+func (kp QKnapsackData) Pairs() []PairProfit {
+    return kp.PP
+}
+
+// Check the sizes, weights and pair profits of a quadratic knapsack problem.
+// The pair profits must be non-negative, the bounds rely on it.
+func CheckQKPData(kp *QKnapsackData) error {
+    if len(kp.P) != kp.Dim || len(kp.W) != kp.Dim {
+	return errors.New("wrong input: profits, weights and problem have different sizes")
+    }
+    if kp.C < 0 {
+	return errors.New("wrong input: negative capacity")
+    }
+    for _,w := range kp.W {
+	if w < 0 {
+	    return errors.New("wrong input: negative weight")
+	}
+    }
+    for _,pp := range kp.PP {
+	if pp.I < 0 || pp.I >= kp.Dim || pp.J < 0 || pp.J >= kp.Dim || pp.I == pp.J {
+	    return errors.New("wrong input: pair of items out of range")
+	}
+	if pp.P < 0 {
+	    return errors.New("wrong input: negative pair profit")
+	}
+    }
+    return nil
+}
+
+// Symmetric matrix of the pair profits, q[i][i] = 0.
+// Pairs given twice (also as J,I) add up.
+func qMatrix(kp QuadraticKnapsackProblem) [][]int {
+    n := kp.N()
+    q := make([][]int,n)
+    for i:=0 ; i<n ; i++ {
+	q[i] = make([]int,n)
+    }
+    for _,pp := range kp.Pairs() {
+	q[pp.I][pp.J] += pp.P
+	q[pp.J][pp.I] += pp.P
+    }
+    return q
+}
+
+// Objective function value of a solution of a quadratic knapsack problem.
+func qkpValue(kp QuadraticKnapsackProblem, q [][]int, x []int) int {
+    z := 0
+    for i,xi := range x {
+	if xi == 0 {
+	    continue
+	}
+	z += kp.Profit(i)
+	for j:=i+1 ; j<len(x) ; j++ {
+	    z += x[j]*q[i][j]
+	}
+    }
+    return z
+}
+
+// Upper plane relaxation of a quadratic knapsack problem (in the style of
+// Caprara, Pisinger and Toth).
+//
+// For a set T of selected items the objective is the sum over i in T of
+// Profit[i] + 1/2 * sum_{j in T} q[i][j]. If u[i] bounds the pair profits
+// item i can get from a feasible partner set (the LP bound of a knapsack
+// problem with the profits q[i][j] and the capacity Capacity-Weight[i]),
+// the linear function sum (2*Profit[i]+u[i])*X[i] is an upper plane of twice
+// the objective. Its maximum over the knapsack constraint (again by the LP
+// bound) gives the upper bound.
+type qkpBound struct {
+    q        [][]int
+    partners [][]int			// partners j of item i with q[i][j] > 0,
+					// by decreasing q[i][j]/Weight[j]
+}
+
+func newQKPBound(kp QuadraticKnapsackProblem, q [][]int) *qkpBound {
+    n := kp.N()
+    b := &qkpBound{ q: q, partners: make([][]int,n) }
+    for i:=0 ; i<n ; i++ {
+	for j:=0 ; j<n ; j++ {
+	    if q[i][j] > 0 {
+		b.partners[i] = append(b.partners[i], j)
+	    }
+	}
+	p := b.partners[i]
+	sort.SliceStable(p, func(a, c int) bool {	// cross multiplication: weights may be 0
+	    return int64(q[i][p[a]])*int64(kp.Weight(p[c])) > int64(q[i][p[c]])*int64(kp.Weight(p[a]))
+	})
+    }
+    return b
+}
+
+// Upper bound for the free items with the linear profits lin[i] (Profit[i]
+// plus the pair profits with the selected items) and the residual capacity r.
+// If pi is not nil, the coefficients 2*lin[i]+u[i] of the upper plane are
+// stored in pi.
+func (b *qkpBound) bound(kp QuadraticKnapsackProblem, free []bool, lin []int, r int, pi []int) int {
+    var items []int
+
+    n := kp.N()
+    coef := make([]int,n)
+    for i:=0 ; i<n ; i++ {
+	if !free[i] || kp.Weight(i) > r {
+	    continue
+	}
+	u := 0				// LP bound of the partners of item i
+	c := r - kp.Weight(i)
+	for _,j := range b.partners[i] {
+	    if !free[j] {
+		continue
+	    }
+	    if kp.Weight(j) <= c {
+		u += b.q[i][j]
+		c -= kp.Weight(j)
+		continue
+	    }
+	    u += int(int64(b.q[i][j]) * int64(c) / int64(kp.Weight(j)))
+	    break
+	}
+	coef[i] = 2*lin[i] + u
+	if pi != nil {
+	    pi[i] = coef[i]
+	}
+	if coef[i] > 0 {
+	    items = append(items, i)
+	}
+    }
+
+    sort.SliceStable(items, func(a, c int) bool {
+	i, j := items[a], items[c]
+	return int64(coef[i])*int64(kp.Weight(j)) > int64(coef[j])*int64(kp.Weight(i))
+    })
+    ub := 0				// LP bound of the upper plane
+    for _,i := range items {
+	if kp.Weight(i) <= r {
+	    ub += coef[i]
+	    r -= kp.Weight(i)
+	    continue
+	}
+	ub += int(int64(coef[i]) * int64(r) / int64(kp.Weight(i)))
+	break
+    }
+    return ub/2
+}
+
+// Upper bound for a quadratic knapsack problem by the upper plane relaxation
+// (see qkpBound).
+func QKPUpperBound(kp QuadraticKnapsackProblem) int {
+    n := kp.N()
+    free := make([]bool,n)
+    lin := make([]int,n)
+    for i:=0 ; i<n ; i++ {
+	free[i] = true
+	lin[i] = kp.Profit(i)
+    }
+    return newQKPBound(kp, qMatrix(kp)).bound(kp, free, lin, kp.Capacity(), nil)
+}
+
+// Solve a quadratic knapsack problem by a greedy heuristic.
+//
+// We add items by decreasing gain per weight, where the gain of an item is
+// its profit plus the pair profits with the selected items, as long as an
+// item with positive gain fits. Then we exchange a selected item with an
+// unselected one while this improves the solution, and add items again.
+func QKPGreedy(kp QuadraticKnapsackProblem) ([]int,int) {
+    n := kp.N()
+    q := qMatrix(kp)
+    x := make([]int,n)
+    r := kp.Capacity()			// residual capacity
+    gain := make([]int,n)		// Profit[i] + pair profits with the selected items
+    for i:=0 ; i<n ; i++ {
+	gain[i] = kp.Profit(i)
+    }
+    set := func(i int, xi int) {	// select (xi=1) or remove (xi=0) item i
+	d := 2*xi - 1
+	x[i] = xi
+	r -= d*kp.Weight(i)
+	for j:=0 ; j<n ; j++ {
+	    gain[j] += d*q[i][j]
+	}
+    }
+    fill := func() {			// add items by decreasing gain/weight
+	for {
+	    best := -1
+	    for i:=0 ; i<n ; i++ {
+		if x[i] == 1 || kp.Weight(i) > r || gain[i] <= 0 {
+		    continue
+		}
+		if best < 0 || int64(gain[i])*int64(kp.Weight(best)) > int64(gain[best])*int64(kp.Weight(i)) {
+		    best = i
+		}
+	    }
+	    if best < 0 {
+		return
+	    }
+	    set(best, 1)
+	}
+    }
+
+    fill()
+    for {				// best improving exchange of k and i
+	bk, bi, bd := -1, -1, 0
+	for k:=0 ; k<n ; k++ {
+	    if x[k] == 0 {
+		continue
+	    }
+	    for i:=0 ; i<n ; i++ {
+		if x[i] == 1 || kp.Weight(i) - kp.Weight(k) > r {
+		    continue
+		}
+		if d := gain[i] - q[i][k] - gain[k]; d > bd {
+		    bk, bi, bd = k, i, d
+		}
+	    }
+	}
+	if bk < 0 {
+	    break
+	}
+	set(bk, 0)
+	set(bi, 1)
+	fill()
+    }
+
+    return x, qkpValue(kp, q, x)
+}
+
+// Solve a quadratic knapsack problem by branch and bound.
+//
+// A depth first search branches on the items by decreasing ratio of the
+// upper plane coefficients of the root (see qkpBound), X[i]=1 first.
+// Selecting an item adds its pair profits to the linear profits of the other
+// items. A state is pruned, if its profit plus the upper plane bound of the
+// free items doesn't exceed the best solution. The solution of QKPGreedy()
+// is the initial incumbent.
+func QKPBranchAndBound(kp QuadraticKnapsackProblem) ([]int,int) {
+    var dfs func(k int, psum int, r int)
+
+    n := kp.N()
+    q := qMatrix(kp)
+    b := newQKPBound(kp, q)
+    xb, zb := QKPGreedy(kp)		// best solution
+    x := make([]int,n)
+    free := make([]bool,n)
+    lin := make([]int,n)		// linear profits for the actual selection
+    order := make([]int,n)
+    for i:=0 ; i<n ; i++ {
+	free[i] = true
+	lin[i] = kp.Profit(i)
+	order[i] = i
+    }
+    pi := make([]int,n)
+    b.bound(kp, free, lin, kp.Capacity(), pi)
+    sort.SliceStable(order, func(a, c int) bool {
+	i, j := order[a], order[c]
+	return int64(pi[i])*int64(kp.Weight(j)) > int64(pi[j])*int64(kp.Weight(i))
+    })
+
+    dfs = func(k int, psum int, r int) {
+	if k == n {
+	    if psum > zb {
+		zb = psum
+		copy(xb, x)
+	    }
+	    return
+	}
+	if psum + b.bound(kp, free, lin, r, nil) <= zb {	// prune
+	    return
+	}
+	i := order[k]
+	free[i] = false
+	if kp.Weight(i) <= r {		// X[i] = 1
+	    p := lin[i]
+	    for j:=0 ; j<n ; j++ {
+		lin[j] += q[i][j]
+	    }
+	    x[i] = 1
+	    dfs(k+1, psum+p, r-kp.Weight(i))
+	    x[i] = 0
+	    for j:=0 ; j<n ; j++ {
+		lin[j] -= q[i][j]
+	    }
+	}
+	dfs(k+1, psum, r)		// X[i] = 0
+	free[i] = true
+    }
+    dfs(0, 0, kp.Capacity())
+
+    return xb,zb
+}
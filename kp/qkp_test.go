@@ -0,0 +1,86 @@
+package kp
+
+import (
+    "math/rand"
+    "testing"
+)
+
+// Objective function value of x, -1 if x is not a feasible solution of the
+// quadratic knapsack problem.
+func objectiveQKP(kp QKnapsackData, x []int) int {
+    z := objective(kp, x)
+    if z < 0 {
+	return -1
+    }
+    for _,pp := range kp.PP {
+	z += pp.P*x[pp.I]*x[pp.J]
+    }
+    return z
+}
+
+func TestQuadratic(t *testing.T) {
+    kps := []QKnapsackData{
+	{ Name: "pair is better", Dim: 3, P: []int{10,4,4}, W: []int{4,2,2}, C: 4,
+	  PP: []PairProfit{ { I: 1, J: 2, P: 5 } } },
+	{ Name: "pair given twice", Dim: 3, P: []int{10,4,4}, W: []int{4,2,2}, C: 4,
+	  PP: []PairProfit{ { I: 1, J: 2, P: 1 }, { I: 2, J: 1, P: 2 } } },
+	{ Name: "no pairs", Dim: 3, P: []int{6,10,12}, W: []int{1,2,3}, C: 5 },
+    }
+    r := rand.New(rand.NewSource(1))
+    for k:=0 ; k<40 ; k++ {
+	n := 1 + r.Intn(12)
+	kp := QKnapsackData{ Name: "random", Dim: n, P: make([]int,n), W: make([]int,n) }
+	wsum := 0
+	for i:=0 ; i<n ; i++ {
+	    kp.P[i] = r.Intn(30)
+	    kp.W[i] = 1 + r.Intn(20)
+	    wsum += kp.W[i]
+	    for j:=i+1 ; j<n ; j++ {
+		if r.Intn(3) == 0 {
+		    kp.PP = append(kp.PP, PairProfit{ I: i, J: j, P: r.Intn(20) })
+		}
+	    }
+	}
+	kp.C = r.Intn(wsum+1)
+	kps = append(kps, kp)
+    }
+
+    for _,kp := range kps {
+	if err := CheckQKPData(&kp); err != nil {
+	    t.Fatal(err)
+	}
+	value := func(x []int) int { return objectiveQKP(kp, x) }
+	zopt := enumerateBy(kp.N(), value)
+	if ub := QKPUpperBound(kp); ub < zopt {
+	    t.Errorf("%s: upper bound %v is less than the optimum %v", kp.Name, ub, zopt)
+	}
+
+	x,z := QKPGreedy(kp)
+	checkSolution(t, "greedy, " + kp.Name, value, x, z, zopt, false)
+
+	x,z = QKPBranchAndBound(kp)
+	checkSolution(t, "bab, " + kp.Name, value, x, z, zopt, true)
+    }
+}
+
+func TestCheckQKPData(t *testing.T) {
+    pair := []PairProfit{ { I: 0, J: 1, P: 3 } }
+    tests := []struct {
+	name string
+	kp   QKnapsackData
+	ok   bool
+    }{
+	{ "valid", QKnapsackData{ Dim: 2, P: []int{1,2}, W: []int{1,2}, C: 2, PP: pair }, true },
+	{ "size", QKnapsackData{ Dim: 2, P: []int{1}, W: []int{1,2}, C: 2, PP: pair }, false },
+	{ "negative capacity", QKnapsackData{ Dim: 2, P: []int{1,2}, W: []int{1,2}, C: -2, PP: pair }, false },
+	{ "negative weight", QKnapsackData{ Dim: 2, P: []int{1,2}, W: []int{1,-2}, C: 2, PP: pair }, false },
+	{ "out of range", QKnapsackData{ Dim: 2, P: []int{1,2}, W: []int{1,2}, C: 2, PP: []PairProfit{ { I: 0, J: 2, P: 3 } } }, false },
+	{ "same item", QKnapsackData{ Dim: 2, P: []int{1,2}, W: []int{1,2}, C: 2, PP: []PairProfit{ { I: 1, J: 1, P: 3 } } }, false },
+	{ "negative pair profit", QKnapsackData{ Dim: 2, P: []int{1,2}, W: []int{1,2}, C: 2, PP: []PairProfit{ { I: 0, J: 1, P: -3 } } }, false },
+    }
+    for _,test := range tests {
+	if err := CheckQKPData(&test.kp); (err == nil) != test.ok {
+	    t.Errorf("%s: %v", test.name, err)
+	}
+    }
+}
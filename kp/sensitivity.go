@@ -0,0 +1,92 @@
+package kp
+
+import (
+    "errors"
+)
+
+// Sensitivity range of the profit of an item
+type ProfitRange struct {
+    Item int  `json:"item"`	// item index
+    X    int  `json:"x"`		// decision of the item in the optimal solution
+    Min  *int `json:"min"`	// minimal profit keeping the solution optimal, nil: unbounded
+    Max  *int `json:"max"`	// maximal profit keeping the solution optimal, nil: unbounded
+}
+
+// Sensitivity analysis for the profits of an optimal solution x.
+//
+// For each item i we compute the range of its profit such that x remains
+// optimal (all other data unchanged, the bounds are inclusive, i.e. at the
+// bound there is an alternative optimal solution):
+//   - X[i]=1: the profit may decrease by z - z0[i], where z0[i] is the optimal
+//     value with X[i]=0 fixed. There is no upper bound.
+//   - X[i]=0: the profit may increase by z - z1[i], where z1[i] is the optimal
+//     value with X[i]=1 fixed. There is no lower bound and if item i doesn't
+//     fit into the knapsack at all, there is no upper bound, too.
+//
+// x may be computed by any exact solver. The values z0[i] and z1[i] are
+// computed for all items at once by combining the value function of the items
+// 0,...,i-1 (forward) and i+1,...,n-1 (backward), which takes O(n*Capacity)
+// time and memory like DynProg().
+// The decision of a fixed item (see FixedItemsProblem) doesn't depend on its
+// profit, so its range is unbounded. The ranges of the free items are the
+// ranges of the reduced problem.
+// An error is returned if x is not an optimal solution (see CheckIncumbent()
+// for the errors of an invalid x).
+func Sensitivity(kp KnapsackProblem, x []int) ([]ProfitRange,error) {
+    n := kp.N()
+    c := kp.Capacity()
+    z, err := CheckIncumbent(kp, x, false)	// a feasible binary vector?
+    if err != nil {
+	return nil, err
+    }
+    if r, err := fixedReduction(kp); err != nil {
+	return nil, err
+    } else if r != nil {			// fixed items: analyze the reduced problem
+	return r.sensitivity(x)
+    }
+
+    bw := valueTable(kp)			// bw[i][s]: items i,...,n-1
+    fw := make([][]int, n+1)			// fw[i][s]: items 0,...,i-1
+    fw[0] = make([]int, c+1)
+    for i:=0 ; i<n ; i++ {
+	fw[i+1] = make([]int, c+1)
+	for s:=0 ; s<=c ; s++ {
+	    fw[i+1][s] = fw[i][s]
+	    if s >= kp.Weight(i) && fw[i+1][s] < kp.Profit(i) + fw[i][s-kp.Weight(i)] {
+		fw[i+1][s] = kp.Profit(i) + fw[i][s-kp.Weight(i)]
+	    }
+	}
+    }
+
+    if z != bw[0][c] {				// check the solution
+	return nil, errors.New("solution is not optimal")
+    }
+
+    ranges := make([]ProfitRange, n)
+    for i:=0 ; i<n ; i++ {
+	ranges[i].Item = i
+	ranges[i].X = x[i]
+	if x[i] == 1 {				// z0 = best value without item i
+	    z0 := 0
+	    for s:=0 ; s<=c ; s++ {
+		if v := fw[i][s] + bw[i+1][c-s]; v > z0 {
+		    z0 = v
+		}
+	    }
+	    min := kp.Profit(i) - (z - z0)
+	    ranges[i].Min = &min
+	} else if kp.Weight(i) <= c {		// z1 = best value with item i
+	    cr := c - kp.Weight(i)
+	    z1 := 0
+	    for s:=0 ; s<=cr ; s++ {
+		if v := fw[i][s] + bw[i+1][cr-s]; v > z1 {
+		    z1 = v
+		}
+	    }
+	    max := z - z1
+	    ranges[i].Max = &max
+	}
+    }
+
+    return ranges, nil
+}
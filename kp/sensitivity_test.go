@@ -0,0 +1,55 @@
+package kp
+
+import (
+    "testing"
+)
+
+// Is x optimal if the profit of item i is p?
+func optimalWithProfit(kp KnapsackData, x []int, i int, p int) bool {
+    kp.P = append([]int(nil), kp.P...)
+    kp.P[i] = p
+    return objective(kp, x) == enumerate(kp)
+}
+
+func TestSensitivity(t *testing.T) {
+    for _,kp := range testProblems(t) {
+	x,_ := DynProg(kp)
+	ranges, err := Sensitivity(kp, x)
+	if err != nil {
+	    t.Errorf("%s: %v", kp.Name, err)
+	    continue
+	}
+	for i,r := range ranges {
+	    if r.Item != i || r.X != x[i] {
+		t.Errorf("%s: range %v is %+v", kp.Name, i, r)
+	    }
+	    if (x[i] == 1 && r.Max != nil) || (x[i] == 0 && r.Min != nil) {
+		t.Errorf("%s: range of item %v should be unbounded", kp.Name, i)
+	    }
+	    if r.Min != nil {		// the bounds are inclusive and tight
+		if !optimalWithProfit(kp, x, i, *r.Min) || optimalWithProfit(kp, x, i, *r.Min-1) {
+		    t.Errorf("%s: minimal profit %v of item %v is wrong", kp.Name, *r.Min, i)
+		}
+	    }
+	    if r.Max != nil {
+		if !optimalWithProfit(kp, x, i, *r.Max) || optimalWithProfit(kp, x, i, *r.Max+1) {
+		    t.Errorf("%s: maximal profit %v of item %v is wrong", kp.Name, *r.Max, i)
+		}
+	    }
+	    if r.Max == nil && x[i] == 0 && kp.W[i] <= kp.C {
+		t.Errorf("%s: item %v fits, but its maximal profit is unbounded", kp.Name, i)
+	    }
+	}
+    }
+
+    kp := testProblems(t)[3]		// greedy is not optimal
+    x,_ := Greedy(kp)
+    if _,err := Sensitivity(kp, x); err == nil {
+	t.Error("no error for a solution which is not optimal")
+    }
+    for _,x := range [][]int{ {0,1}, {0,2,0}, {1,-1,1}, {1,1,1} } {	// size, binary, feasible
+	if _,err := Sensitivity(kp, x); err == nil {
+	    t.Errorf("no error for the invalid solution %v", x)
+	}
+    }
+}
@@ -0,0 +1,269 @@
+package kp
+
+import (
+    "fmt"
+    "sort"
+)
+
+// A view on a subset of the items of a knapsack problem with its own capacity.
+// Item i of the view is item items[i] of the underlying problem.
+type itemView struct {
+    kp    KnapsackProblem	// underlying knapsack problem
+    items []int			// items of the view
+    c     int			// capacity
+}
+
+func (v itemView) N() int {
+    return len(v.items)
+}
+
+func (v itemView) Capacity() int {
+    return v.c
+}
+
+func (v itemView) Profit(i int) int {
+    return v.kp.Profit(v.items[i])
+}
+
+func (v itemView) Weight(i int) int {
+    return v.kp.Weight(v.items[i])
+}
+
+// A solver session for incremental re-optimization.
+//
+// The items are addressed by their index in the order of insertion, removing
+// an item shifts the indices of the following items. After some edits,
+// Solve() re-optimizes with the previous solution as warm start: it is adapted
+// to the edited instance, repaired if infeasible and improved greedily. Its
+// value z is a lower bound for the new optimum, so the branch and bound search
+// prunes all states with an upper bound below z right from the start.
+// If all edits since the last Solve() restrict the problem (remove an item
+// which isn't fixed to 1, lower a profit, raise a weight, reduce the capacity,
+// fix a free item), the previous optimum is an upper bound for the new one and
+// the search stops as soon as it reaches this bound.
+//
+// Only the previous solution and its value are reused. The search tree
+// (agenda and upper bounds of the states) of the previous run is not kept:
+// an edit changes the item order and the upper bounds of all states, so
+// Solve() starts a new search.
+//
+// The solution is the same as BranchAndBoundHSOpt() with opt.NoWarmStart
+// computes for the free items sorted (stable) according to decreasing
+// profit/weight, i.e. the first optimal solution in depth first order. It may
+// differ from BranchAndBoundHS(), which starts with the solution of ExtGreedy()
+// and keeps it, if it is optimal.
+//
+// Items may be fixed (see FixItem()), Solve() respects the fix markers.
+// The edits keep the items fixed to 1 within the capacity.
+type Session struct {
+    p   []int		// profits
+    w   []int		// weights
+    c   int		// capacity
+    fix []int		// fix markers (FixFree, FixIn, FixOut)
+    x   []int		// previous solution (warm start), nil if there is none
+    ub  int		// upper bound of the optimal value (previous optimum), -1: none
+}
+
+// Start a session for a knapsack problem.
+// The items need not to be sorted, the session sorts them itself.
+// The fix markers of the problem (see FixedItemsProblem) are taken over.
+// An error is returned if they are invalid or the items fixed to 1 don't fit.
+func NewSession(kp KnapsackProblem) (*Session,error) {
+    n := kp.N()
+    s := &Session{ p: make([]int,n), w: make([]int,n), c: kp.Capacity(), fix: make([]int,n), ub: -1 }
+    for i:=0 ; i<n ; i++ {
+	s.p[i] = kp.Profit(i)
+	s.w[i] = kp.Weight(i)
+    }
+    if fp, ok := kp.(FixedItemsProblem); ok && fp.FixedItems() != nil {
+	if _,err := Reduce(kp, fp.FixedItems()); err != nil {
+	    return nil, err
+	}
+	copy(s.fix, fp.FixedItems())
+    }
+    return s, nil
+}
+
+// The actual knapsack problem of the session.
+func (s *Session) Problem() KnapsackData {
+    return KnapsackData{ Type: "KP", Dim: len(s.p), C: s.c,
+			 P: append([]int(nil), s.p...), W: append([]int(nil), s.w...),
+			 Fix: append([]int(nil), s.fix...) }
+}
+
+// Add an item, returns its index.
+func (s *Session) AddItem(p int, w int) (int,error) {
+    if p <= 0 || w <= 0 {
+	return 0, fmt.Errorf("profit and weight must be positive: %v, %v", p, w)
+    }
+    s.p = append(s.p, p)
+    s.w = append(s.w, w)
+    s.fix = append(s.fix, FixFree)
+    s.ub = -1
+    if s.x != nil {
+	s.x = append(s.x, 0)		// warm start: the new item is not packed
+    }
+    return len(s.p)-1, nil
+}
+
+// Remove item i.
+func (s *Session) RemoveItem(i int) error {
+    if err := s.checkItem(i); err != nil {
+	return err
+    }
+    if s.fix[i] == FixIn {			// frees capacity
+	s.ub = -1
+    }
+    s.p = append(s.p[:i], s.p[i+1:]...)
+    s.w = append(s.w[:i], s.w[i+1:]...)
+    s.fix = append(s.fix[:i], s.fix[i+1:]...)
+    if s.x != nil {
+	s.x = append(s.x[:i], s.x[i+1:]...)
+    }
+    return nil
+}
+
+// Change the profit of item i.
+func (s *Session) ChangeProfit(i int, p int) error {
+    if err := s.checkItem(i); err != nil {
+	return err
+    }
+    if p <= 0 {
+	return fmt.Errorf("profit must be positive: %v", p)
+    }
+    if p > s.p[i] {
+	s.ub = -1
+    }
+    s.p[i] = p
+    return nil
+}
+
+// Change the weight of item i.
+func (s *Session) ChangeWeight(i int, w int) error {
+    if err := s.checkItem(i); err != nil {
+	return err
+    }
+    if w <= 0 {
+	return fmt.Errorf("weight must be positive: %v", w)
+    }
+    if s.fix[i] == FixIn && s.fixedWeight() - s.w[i] + w > s.c {
+	return fmt.Errorf("infeasible: items fixed to 1 exceed the capacity")
+    }
+    if w < s.w[i] {
+	s.ub = -1
+    }
+    s.w[i] = w
+    return nil
+}
+
+// Change the capacity of the knapsack.
+func (s *Session) ChangeCapacity(c int) error {
+    if c < 0 {
+	return fmt.Errorf("capacity must not be negative: %v", c)
+    }
+    if s.fixedWeight() > c {
+	return fmt.Errorf("infeasible: items fixed to 1 exceed the capacity")
+    }
+    if c > s.c {
+	s.ub = -1
+    }
+    s.c = c
+    return nil
+}
+
+// Fix item i: FixIn (must be selected), FixOut (must not be selected) or
+// FixFree (the solver decides).
+func (s *Session) FixItem(i int, f int) error {
+    if err := s.checkItem(i); err != nil {
+	return err
+    }
+    switch f {
+    case FixFree, FixOut:
+    case FixIn:
+	if s.fix[i] != FixIn && s.fixedWeight() + s.w[i] > s.c {
+	    return fmt.Errorf("infeasible: items fixed to 1 exceed the capacity")
+	}
+    default:
+	return fmt.Errorf("wrong fix marker for item %v: %v", i, f)
+    }
+    if s.fix[i] != FixFree && s.fix[i] != f {	// releases a fixed item
+	s.ub = -1
+    }
+    s.fix[i] = f
+    return nil
+}
+
+// Total weight of the items fixed to 1.
+func (s *Session) fixedWeight() int {
+    w := 0
+    for i,f := range s.fix {
+	if f == FixIn {
+	    w += s.w[i]
+	}
+    }
+    return w
+}
+
+// Solve the actual knapsack problem, warm started by the previous solution.
+// Only the free items are searched, the fixed items keep their decision.
+func (s *Session) Solve() ([]int,int) {
+    var perm []int
+
+    n := len(s.p)
+    c := s.c - s.fixedWeight()			// residual capacity and profit
+    psum := 0					// of the items fixed to 1
+    for i:=0 ; i<n ; i++ {
+	switch s.fix[i] {
+	case FixFree:
+	    perm = append(perm, i)
+	case FixIn:
+	    psum += s.p[i]
+	}
+    }
+    // sort the free items according to decreasing profit/weight, with the
+    // same ratios as CheckSortedItems() (no overflow for large values)
+    sort.SliceStable(perm, func(a, b int) bool {
+	i, j := perm[a], perm[b]
+	return float64(s.p[i])/float64(s.w[i]) > float64(s.p[j])/float64(s.w[j])
+    })
+    sorted := itemView{ kp: s.Problem(), items: perm, c: c }
+
+    pmax := 0					// the empty knapsack has value 0
+    if s.x != nil {				// warm start
+	ws := make([]int,len(perm))
+	for j,i := range perm {
+	    ws[j] = s.x[i]
+	}
+	if z := repairChromosome(sorted, ws); z > 1 {	// repair and improve
+	    pmax = z-1
+	}
+    }
+    zmax := -1
+    if s.ub >= 0 {				// bound of the free items
+	zmax = s.ub - psum
+    }
+    run := &checkpointT{ agenda: []*stateT{ initialState(sorted) }, pmax: pmax }
+    xs,z,_ := hsSearch(sorted, BabOptions{}, run, zmax)	// no checkpoints, no errors
+    if xs == nil {				// nothing better than the empty knapsack
+	xs = make([]int,len(perm))
+    }
+
+    s.x = make([]int,n)				// back to the original order
+    for i,f := range s.fix {
+	if f == FixIn {
+	    s.x[i] = 1
+	}
+    }
+    for j,i := range perm {
+	s.x[i] = xs[j]
+    }
+    s.ub = z + psum
+    return append([]int(nil), s.x...), z + psum
+}
+
+func (s *Session) checkItem(i int) error {
+    if i < 0 || i >= len(s.p) {
+	return fmt.Errorf("no such item: %v", i)
+    }
+    return nil
+}
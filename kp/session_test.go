@@ -0,0 +1,115 @@
+package kp
+
+import (
+    "fmt"
+    "sort"
+    "testing"
+)
+
+// Solution of BranchAndBoundHSOpt() without warm start for the free items
+// of kp sorted (stable) according to decreasing profit/weight, which the
+// session should find.
+func freshSolve(t *testing.T, kp KnapsackData) []int {
+    var perm []int
+
+    x := make([]int,kp.Dim)
+    sorted := KnapsackData{ C: kp.C }
+    for i:=0 ; i<kp.Dim ; i++ {
+	switch kp.Fix[i] {
+	case FixFree:
+	    perm = append(perm, i)
+	case FixIn:
+	    x[i] = 1
+	    sorted.C -= kp.W[i]
+	}
+    }
+    sort.SliceStable(perm, func(a, b int) bool {
+	i, j := perm[a], perm[b]
+	return float64(kp.P[i])/float64(kp.W[i]) > float64(kp.P[j])/float64(kp.W[j])
+    })
+    for _,i := range perm {
+	sorted.P = append(sorted.P, kp.P[i])
+	sorted.W = append(sorted.W, kp.W[i])
+    }
+    sorted.Dim = len(perm)
+    xs,_,err := BranchAndBoundHSOpt(sorted, BabOptions{ NoWarmStart: true })
+    if err != nil {
+	t.Fatal(err)
+    }
+    for j,i := range perm {
+	x[i] = xs[j]
+    }
+    return x
+}
+
+func TestSession(t *testing.T) {
+    edits := []struct {
+	name string
+	edit func(s *Session) error
+    }{
+	{ "add item", func(s *Session) error { _,err := s.AddItem(30, 20); return err } },
+	{ "remove item", func(s *Session) error { return s.RemoveItem(0) } },
+	{ "change profit", func(s *Session) error { return s.ChangeProfit(2, 60) } },
+	{ "change weight", func(s *Session) error { return s.ChangeWeight(1, 5) } },
+	{ "reduce capacity", func(s *Session) error { return s.ChangeCapacity(s.Problem().C/2) } },
+	{ "add light item", func(s *Session) error { _,err := s.AddItem(3, 1); return err } },
+	{ "increase capacity", func(s *Session) error { return s.ChangeCapacity(3*s.Problem().C) } },
+	{ "lower profit", func(s *Session) error { return s.ChangeProfit(0, 1) } },	// restricting edits:
+	{ "raise weight", func(s *Session) error { return s.ChangeWeight(2, 2*s.Problem().W[2]) } },	// the previous
+	{ "fix item", func(s *Session) error { return s.FixItem(1, FixOut) } },	// optimum is
+	{ "remove another item", func(s *Session) error { return s.RemoveItem(0) } },	// an upper bound
+	{ "release item", func(s *Session) error { return s.FixItem(0, FixFree) } },
+    }
+    kps := append(testProblems(t),
+		  KnapsackData{ Name: "depth first", Dim: 6, P: []int{1,2,2,4,2,2}, W: []int{1,2,2,4,2,2}, C: 4 })
+    for _,kp := range kps {
+	if kp.N() < 3 {
+	    continue
+	}
+	s, err := NewSession(kp)
+	if err != nil {
+	    t.Fatal(err)
+	}
+	x,z := s.Solve()
+	checkOptimal(t, "session", kp, x, z)
+	if xf := freshSolve(t, s.Problem()); fmt.Sprint(xf) != fmt.Sprint(x) {
+	    t.Errorf("%s: x = %v, but a fresh solve gives %v", kp.Name, x, xf)
+	}
+	for _,e := range edits {
+	    if err := e.edit(s); err != nil {
+		t.Fatalf("%s, %s: %v", kp.Name, e.name, err)
+	    }
+	    x,z = s.Solve()
+	    p := s.Problem()
+	    value := func(x []int) int { return objectiveFixed(p, x) }
+	    zopt,_ := enumerateFixed(p)
+	    checkSolution(t, "session, " + e.name + ", " + kp.Name, value, x, z, zopt, true)
+
+	    if xf := freshSolve(t, p); fmt.Sprint(xf) != fmt.Sprint(x) {	// the warm start doesn't
+		t.Errorf("%s, %s: x = %v, but a fresh solve gives %v", kp.Name, e.name, x, xf)	// change the solution
+	    }
+	}
+    }
+}
+
+func TestSessionErrors(t *testing.T) {
+    s, err := NewSession(KnapsackData{ Dim: 2, P: []int{4,3}, W: []int{2,2}, C: 3 })
+    if err != nil {
+	t.Fatal(err)
+    }
+    edits := []struct {
+	name string
+	edit func() error
+    }{
+	{ "remove missing item", func() error { return s.RemoveItem(2) } },
+	{ "negative item", func() error { return s.ChangeProfit(-1, 5) } },
+	{ "zero profit", func() error { _,err := s.AddItem(0, 1); return err } },
+	{ "zero weight", func() error { return s.ChangeWeight(0, 0) } },
+	{ "negative capacity", func() error { return s.ChangeCapacity(-1) } },
+    }
+    for _,e := range edits {
+	if e.edit() == nil {
+	    t.Errorf("%s: no error", e.name)
+	}
+    }
+}
@@ -0,0 +1,108 @@
+package kp
+
+// Parameters of the tabu search
+type TabuParams struct {
+    Iterations int	// iteration budget (number of moves), default: 1000
+    Tenure     int	// number of iterations a flipped item stays tabu,
+			// default: n/4, at least 1
+}
+
+// Solve a knapsack problem by tabu search.
+//
+// We start with the solution of Greedy(). In each iteration we apply the best
+// feasible flip move, i.e. we pack an unpacked item (if it fits) or remove
+// a packed item, even if this worsens the solution. Ties are broken in favour
+// of the smaller weight sum and then the smaller item index.
+//
+// Two memories prevent cycling:
+//   - an item flipped in iteration k is tabu until iteration k+Tenure,
+//   - every visited solution is stored (as 64 bit hash value), moves leading
+//     to a visited solution are tabu.
+// Aspiration criterion: a tabu move is allowed nevertheless if it leads to a
+// solution better than the best solution found so far.
+//
+// The search is completely deterministic. It stops after the iteration budget
+// or if there is no admissible move anymore and returns the best solution found.
+func TabuSearch(kp KnapsackProblem, par TabuParams) ([]int,int,error) {
+    if r, err := fixedReduction(kp); r != nil || err != nil {	// fixed items: solve the reduced problem
+	if err != nil {
+	    return nil,0,err
+	}
+	return solveReduced(r, func(p KnapsackProblem) ([]int,int,error) { return TabuSearch(p, par) })
+    }
+
+    n := kp.N()
+    c := kp.Capacity()
+    if par.Iterations <= 0 {
+	par.Iterations = 1000
+    }
+    if par.Tenure <= 0 {
+	par.Tenure = n/4
+	if par.Tenure < 1 {
+	    par.Tenure = 1
+	}
+    }
+
+    x,z := Greedy(kp)				// start solution
+    xbest := make([]int,n)
+    copy(xbest,x)
+    zbest := z
+    w := 0
+    for i:=0 ; i<n ; i++ {
+	w += x[i]*kp.Weight(i)
+    }
+
+    keys := make([]uint64,n)			// hash keys of the items
+    h := uint64(0)				// hash value of x
+    for i:=0 ; i<n ; i++ {
+	keys[i] = hashKey(uint64(i))
+	if x[i] == 1 {
+	    h ^= keys[i]
+	}
+    }
+    visited := map[uint64]bool{ h: true }	// solution memory
+    tabu := make([]int,n)			// item j is tabu until iteration tabu[j]
+
+    for k:=1 ; k<=par.Iterations ; k++ {
+	jbest := -1				// best admissible move
+	zj, wj := 0, 0
+	for j:=0 ; j<n ; j++ {
+	    znew, wnew := z-kp.Profit(j), w-kp.Weight(j)
+	    if x[j] == 0 {
+		znew, wnew = z+kp.Profit(j), w+kp.Weight(j)
+		if wnew > c {			// infeasible move
+		    continue
+		}
+	    }
+	    if (tabu[j] > k || visited[h^keys[j]]) && znew <= zbest {
+		continue			// tabu and no aspiration
+	    }
+	    if jbest < 0 || znew > zj || (znew == zj && wnew < wj) {
+		jbest, zj, wj = j, znew, wnew
+	    }
+	}
+	if jbest < 0 {				// no admissible move: we are done
+	    break
+	}
+
+	x[jbest] = 1 - x[jbest]			// apply the move
+	z, w = zj, wj
+	h ^= keys[jbest]
+	visited[h] = true
+	tabu[jbest] = k + par.Tenure
+	if z > zbest {
+	    copy(xbest,x)
+	    zbest = z
+	}
+    }
+
+    return xbest,zbest,nil
+}
+
+// Deterministic pseudo random hash key for item i (splitmix64).
+func hashKey(i uint64) uint64 {
+    i += 0x9e3779b97f4a7c15
+    i = (i ^ (i >> 30)) * 0xbf58476d1ce4e5b9
+    i = (i ^ (i >> 27)) * 0x94d049bb133111eb
+    return i ^ (i >> 31)
+}
@@ -0,0 +1,29 @@
+package kp
+
+import (
+    "testing"
+)
+
+func TestTabuSearch(t *testing.T) {
+    params := []TabuParams{
+	{},
+	{ Iterations: 50, Tenure: 1 },
+	{ Iterations: 200, Tenure: 5 },
+    }
+    for _,kp := range testProblems(t) {
+	_,zg := Greedy(kp)
+	for _,par := range params {
+	    x,z,err := TabuSearch(kp, par)
+	    if err != nil {
+		t.Fatal(err)
+	    }
+	    checkFeasible(t, "tabu", kp, x, z)
+	    if z < zg {
+		t.Errorf("%s, %+v: z = %v is worse than the start %v", kp.Name, par, z, zg)
+	    }
+	    if _,z2,_ := TabuSearch(kp, par); z2 != z {	// deterministic
+		t.Errorf("%s, %+v: z = %v and %v", kp.Name, par, z, z2)
+	    }
+	}
+    }
+}
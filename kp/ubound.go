@@ -2,6 +2,7 @@ package kp
 
 import (
     "math"
+    "math/big"
 )
 
 // Upper Bound Procedure for the knapsack Problem
@@ -11,7 +12,15 @@ import (
 // We do not check the precondition here!
 // If the preconditioin is violated, the upper bound value will be probably wrong.
 // Use kp.CheckSortedItems() to check the precondition.
+// If the items fixed to 1 don't fit (see FixedItemsProblem), x is nil,
+// LPRelax() returns the error.
 func UpperBound(kp KnapsackProblem) ([]float64,int) {
+    if r, err := fixedReduction(kp); err != nil {	// fixed items don't fit
+	return nil,0
+    } else if r != nil {			// fixed items: bound of the reduced problem
+	return r.ExpandFrac(UpperBound(r.Problem()))
+    }
+
     n := kp.N()
     x := make([]float64,n)
     ub := 0
@@ -30,6 +39,62 @@ func UpperBound(kp KnapsackProblem) ([]float64,int) {
     return x,ub
 }
 
+// Solution of the LP relaxation of a knapsack problem
+type LPRelaxation struct {
+    Critical     int        `json:"critical"`		// index of the critical item s,
+							// n if all items fit
+    Z            *big.Rat   `json:"z"`			// exact objective function value
+    Dual         *big.Rat   `json:"dual"`		// dual price of the capacity
+    ReducedCosts []*big.Rat `json:"reducedcosts"`	// reduced cost of each item
+}
+
+// Solve the LP relaxation of a knapsack problem exactly.
+//
+// The items 0,...,s-1 are packed completely, the critical item s is packed
+// fractionally: x[s] = (C - w[0] - ... - w[s-1]) / w[s].
+// The dual price of the capacity is lambda = p[s]/w[s] (0 if all items fit)
+// and the reduced cost of item i is p[i] - lambda*w[i]. Items with a reduced
+// cost near 0 are marginal: their decision in an optimal solution of the
+// knapsack problem is most uncertain.
+//
+// Precondition: Profit[i]/Weight[i] >= Profit[i-1]/Weight[i-1] for i=1,...,n-1
+//
+// We do not check the precondition here!
+// Use kp.CheckSortedItems() to check the precondition.
+// An error is returned if the items fixed to 1 don't fit (see FixedItemsProblem).
+func LPRelax(kp KnapsackProblem) ([]float64,LPRelaxation,error) {
+    if r, err := fixedReduction(kp); err != nil {	// fixed items don't fit
+	return nil,LPRelaxation{},err
+    } else if r != nil {			// fixed items: LP relaxation of the reduced problem
+	x,lp,_ := LPRelax(r.Problem())		// no fixed items, no error
+	x,_ = r.ExpandFrac(x,0)
+	return x,r.ExpandLP(lp),nil
+    }
+
+    n := kp.N()
+    x := make([]float64,n)
+    lp := LPRelaxation{ Z: new(big.Rat), Dual: new(big.Rat), ReducedCosts: make([]*big.Rat,n) }
+
+    lp.Critical = criticalItem(kp)
+    c := kp.Capacity()
+    for i:=0 ; i<lp.Critical ; i++ {
+	x[i] = 1.0
+	c -= kp.Weight(i)
+	lp.Z.Add(lp.Z, big.NewRat(int64(kp.Profit(i)), 1))
+    }
+    if s := lp.Critical; s<n {
+	x[s] = float64(c) / float64(kp.Weight(s))
+	lp.Z.Add(lp.Z, big.NewRat(int64(kp.Profit(s))*int64(c), int64(kp.Weight(s))))
+	lp.Dual.SetFrac64(int64(kp.Profit(s)), int64(kp.Weight(s)))
+    }
+    for i:=0 ; i<n ; i++ {
+	rc := new(big.Rat).Mul(lp.Dual, big.NewRat(int64(kp.Weight(i)), 1))
+	lp.ReducedCosts[i] = rc.Sub(big.NewRat(int64(kp.Profit(i)), 1), rc)
+    }
+
+    return x,lp,nil
+}
+
 // Upper bound procedure for use within branch and bound algorithms.
 // Uses the same algorithm as UpperBound(), but only returns the upper bound value.
 // Moreover it is applicable to a subset of the items starting with index istart and
@@ -47,3 +112,16 @@ func uBound1P(kp KnapsackProblem, c int, istart int) int {
     }
     return ub
 }
+
+// Index of the critical item (also known as break item), i.e. the first item
+// which doesn't fit into the knapsack anymore if we pack the items in
+// the given order. If all items fit, the result is n.
+func criticalItem(kp KnapsackProblem) int {
+    n := kp.N()
+    c := kp.Capacity()
+    i := 0
+    for ; i<n && kp.Weight(i)<=c ; i++ {
+	c -= kp.Weight(i)
+    }
+    return i
+}
@@ -0,0 +1,59 @@
+package kp
+
+import (
+    "math"
+    "math/big"
+    "testing"
+)
+
+func TestLPRelax(t *testing.T) {
+    for _,kp := range testProblems(t) {
+	x,lp,err := LPRelax(kp)
+	if err != nil {
+	    t.Fatal(err)
+	}
+	n := kp.N()
+
+	w := 0.0			// x is a feasible solution of the LP relaxation
+	for i:=0 ; i<n ; i++ {
+	    if x[i] < 0 || x[i] > 1 {
+		t.Errorf("%s: x[%v] = %v", kp.Name, i, x[i])
+	    }
+	    w += x[i]*float64(kp.W[i])
+	}
+	if w > float64(kp.C) + 1e-9 {
+	    t.Errorf("%s: x = %v exceeds the capacity", kp.Name, x)
+	}
+
+	if lp.Z.Cmp(big.NewRat(int64(enumerate(kp)), 1)) < 0 {
+	    t.Errorf("%s: z = %v is less than the optimum", kp.Name, lp.Z)
+	}
+	zf,_ := lp.Z.Float64()
+	if _,ub := UpperBound(kp); ub != int(math.Floor(zf)) {
+	    t.Errorf("%s: z = %v, but UpperBound() = %v", kp.Name, lp.Z, ub)
+	}
+
+	// Strong duality: z = Dual*Capacity + sum of the positive reduced costs
+	dz := new(big.Rat).Mul(lp.Dual, big.NewRat(int64(kp.C), 1))
+	for i:=0 ; i<n ; i++ {
+	    rc := new(big.Rat).Mul(lp.Dual, big.NewRat(int64(kp.W[i]), 1))
+	    rc.Sub(big.NewRat(int64(kp.P[i]), 1), rc)
+	    if rc.Cmp(lp.ReducedCosts[i]) != 0 {
+		t.Errorf("%s: reduced cost of item %v is %v instead of %v", kp.Name, i, lp.ReducedCosts[i], rc)
+	    }
+	    if rc.Sign() > 0 {
+		dz.Add(dz, rc)
+	    }
+	}
+	if dz.Cmp(lp.Z) != 0 {
+	    t.Errorf("%s: z = %v, but the dual value is %v", kp.Name, lp.Z, dz)
+	}
+	ws := 0				// the items before the critical item fit,
+	for i:=0 ; i<lp.Critical ; i++ {	// the critical item doesn't fit anymore
+	    ws += kp.W[i]
+	}
+	if ws > kp.C || (lp.Critical < n && ws + kp.W[lp.Critical] <= kp.C) {
+	    t.Errorf("%s: item %v is not critical", kp.Name, lp.Critical)
+	}
+    }
+}
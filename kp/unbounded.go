@@ -0,0 +1,253 @@
+package kp
+
+import (
+    "math"
+)
+
+// Unbounded knapsack problem: every item may be selected any number of times,
+// i.e. X[i] is a non-negative integer. We use the usual KnapsackProblem
+// interface, all weights must be positive (see CheckPositiveWeights()).
+
+// Solve an unbounded knapsack problem with dynamic programming.
+//
+// v[s] is the maximal profit for capacity s:
+// v[s] = max(v[s-1], Profit[i] + v[s-Weight[i]] for all i with Weight[i] <= s).
+//
+// The value function becomes periodic: let b be the item with the best
+// profit/weight ratio. If v[s] = Profit[b] + v[s-Weight[b]] holds for
+// maxWeight consecutive capacities s >= Weight[b], it holds for all larger
+// capacities (by induction, all capacities s-Weight[i] and s-1 of the
+// recursion lie in this window or above). So we stop the computation there
+// and fill the remaining capacity with copies of item b. For large capacities
+// this saves most of the O(n*Capacity) work.
+func UnboundedDynProg(kp KnapsackProblem) ([]int,int,error) {
+    if err := noFixedItems(kp); err != nil {
+	return nil,0,err
+    }
+
+    n := kp.N()
+    c := kp.Capacity()
+    x := make([]int,n)
+    if n == 0 {
+	return x,0,nil
+    }
+
+    b := 0				// item with the best ratio
+    wmax := 0				// maximal weight
+    for i:=0 ; i<n ; i++ {
+	if kp.Profit(i)*kp.Weight(b) > kp.Profit(b)*kp.Weight(i) {
+	    b = i
+	}
+	if kp.Weight(i) > wmax {
+	    wmax = kp.Weight(i)
+	}
+    }
+    pb, wb := kp.Profit(b), kp.Weight(b)
+
+    v := make([]int, 1, c+1)		// v[0] = 0
+    policy := make([]int, 1, c+1)	// item added for capacity s, -1: capacity s is
+    policy[0] = -1			// not used completely (v[s] = v[s-1])
+    run := 0				// number of consecutive periodic capacities
+    for s:=1 ; s<=c ; s++ {
+	vs, ps := v[s-1], -1
+	for i:=0 ; i<n ; i++ {
+	    if kp.Weight(i) <= s && kp.Profit(i) + v[s-kp.Weight(i)] > vs {
+		vs, ps = kp.Profit(i) + v[s-kp.Weight(i)], i
+	    }
+	}
+	v = append(v, vs)
+	policy = append(policy, ps)
+	if s >= wb && vs == pb + v[s-wb] {
+	    run++
+	} else {
+	    run = 0
+	}
+	if run >= wmax {		// periodic from here on
+	    break
+	}
+    }
+
+    s := len(v)-1			// largest computed capacity
+    if s < c {				// fill the rest with copies of item b,
+	x[b] = (c-s+wb-1)/wb		// such that c - x[b]*wb is in the window
+    }
+    s = c - x[b]*wb
+    z := v[s] + x[b]*pb
+    for s > 0 {				// go through the optimal decisions
+	if i := policy[s]; i < 0 {
+	    s--
+	} else {
+	    x[i]++
+	    s -= kp.Weight(i)
+	}
+    }
+
+    return x,z,nil
+}
+
+// Items of an unbounded knapsack problem which are not dominated.
+//
+// Item j is dominated by item i, if floor(Weight[j]/Weight[i]) copies of
+// item i have at least the profit of item j: in any solution we may replace
+// item j by these copies. Of identical items only the first one is kept.
+// The items are returned in their original order.
+func UndominatedItems(kp KnapsackProblem) []int {
+    var items []int
+
+    n := kp.N()
+    for j:=0 ; j<n ; j++ {
+	dominated := false
+	for i:=0 ; i<n && !dominated ; i++ {
+	    if i == j || kp.Weight(i) > kp.Weight(j) {
+		continue
+	    }
+	    if kp.Weight(i) == kp.Weight(j) && kp.Profit(i) == kp.Profit(j) {
+		dominated = i < j		// identical items
+	    } else {
+		dominated = kp.Weight(j)/kp.Weight(i)*kp.Profit(i) >= kp.Profit(j)
+	    }
+	}
+	if !dominated {
+	    items = append(items, j)
+	}
+    }
+    return items
+}
+
+// Solve an unbounded knapsack problem by branch and bound in the style of
+// the algorithm MTU2 by Martello and Toth.
+//
+// Dominated items are eliminated first. Then we solve a core problem of the
+// items with the best ratios (2*sqrt(n) items, at least 10) by a depth first
+// search (MTU1) and check for each remaining item j, whether a solution with
+// X[j] >= 1 may be better: Profit[j] + (Capacity-Weight[j])*Profit[0]/Weight[0]
+// is an upper bound for such solutions. If no item passes, the core solution
+// is optimal, otherwise the core problem is extended by these items and solved
+// again.
+//
+// Precondition: Profit[i]/Weight[i] >= Profit[i-1]/Weight[i-1] for i=1,...,n-1
+//
+// We do not check the precondition here!
+// Use kp.CheckSortedItems() to check the precondition.
+func UnboundedBranchAndBound(kp KnapsackProblem) ([]int,int,error) {
+    if err := noFixedItems(kp); err != nil {
+	return nil,0,err
+    }
+
+    n := kp.N()
+    c := kp.Capacity()
+    x := make([]int,n)
+    items := UndominatedItems(kp)
+    if len(items) == 0 {
+	return x,0,nil
+    }
+
+    size := int(math.Ceil(2*math.Sqrt(float64(n))))	// heuristic core size
+    if size < 10 {
+	size = 10
+    }
+    if size > len(items) {
+	size = len(items)
+    }
+    core := append([]int{}, items[:size]...)
+    rest := items[size:]
+    p0, w0 := kp.Profit(items[0]), kp.Weight(items[0])	// best ratio
+    for {
+	y,z := mtu1(itemView{ kp: kp, items: core, c: c })
+	var add []int
+	for _,j := range rest {		// may item j improve the solution?
+	    w := kp.Weight(j)
+	    if w <= c && kp.Profit(j) + (c-w)*p0/w0 > z {
+		add = append(add, j)
+	    }
+	}
+	if len(add) == 0 {
+	    for k,i := range core {
+		x[i] = y[k]
+	    }
+	    return x,z,nil
+	}
+	core = append(core, add...)	// the ratio order is kept
+	rest = nil
+    }
+}
+
+// Depth first search for the unbounded knapsack problem (MTU1 by Martello
+// and Toth): for item i we try X[i] = floor(c/Weight[i]),...,0 for the
+// residual capacity c. The items must be sorted by decreasing ratio.
+func mtu1(kp KnapsackProblem) ([]int,int) {
+    var dfs func(i int, c int, psum int)
+
+    n := kp.N()
+    x := make([]int,n)
+    xb := make([]int,n)			// best solution
+    zb := 0
+    dfs = func(i int, c int, psum int) {
+	if i == n {
+	    if psum > zb {
+		zb = psum
+		copy(xb, x)
+	    }
+	    return
+	}
+	if psum + uBoundU(kp, c, i) <= zb {	// prune
+	    return
+	}
+	p, w := kp.Profit(i), kp.Weight(i)
+	for k:=c/w ; k>=0 ; k-- {
+	    ub := psum + k*p		// bound for X[i] <= k, decreasing with k
+	    if i+1 < n {
+		ub += (c-k*w)*kp.Profit(i+1)/kp.Weight(i+1)
+	    }
+	    if ub <= zb {
+		break
+	    }
+	    x[i] = k
+	    dfs(i+1, c-k*w, psum+k*p)
+	}
+	x[i] = 0
+    }
+    dfs(0, kp.Capacity(), 0)
+
+    return xb,zb
+}
+
+// Upper bound U3 of Martello and Toth for the unbounded knapsack problem
+// with the items istart,... (sorted by decreasing ratio) and capacity c.
+// We fill the capacity with copies of the first two items and bound the
+// rest by the ratio of the third item (U0), or remove some copies of
+// the first item to get another copy of the second item (U1).
+func uBoundU(kp KnapsackProblem, c int, istart int) int {
+    n := kp.N()
+    if istart >= n {
+	return 0
+    }
+    p1, w1 := kp.Profit(istart), kp.Weight(istart)
+    z1 := c/w1*p1
+    c1 := c%w1
+    if istart+1 >= n {
+	return z1			// only one item: optimal
+    }
+    p2, w2 := kp.Profit(istart+1), kp.Weight(istart+1)
+    z2 := z1 + c1/w2*p2
+    c2 := c1%w2
+    u0 := z2
+    if istart+2 < n {
+	u0 += c2*kp.Profit(istart+2)/kp.Weight(istart+2)
+    }
+    t := (w2-c2+w1-1)/w1		// copies of item 1 to remove
+    u1 := z2 + floorDiv((c2+t*w1)*p2 - t*p1*w2, w2)
+    if u1 > u0 {
+	return u1
+    }
+    return u0
+}
+
+// Integer division rounding towards -infinity.
+func floorDiv(a int, b int) int {
+    q := a/b
+    if a%b != 0 && (a < 0) != (b < 0) {
+	q--
+    }
+    return q
+}
@@ -0,0 +1,62 @@
+package kp
+
+import (
+    "fmt"
+    "testing"
+)
+
+func TestUnbounded(t *testing.T) {
+    solvers := []struct {
+	name  string
+	solve func(KnapsackProblem) ([]int,int,error)
+    }{
+	{ "dp", UnboundedDynProg },
+	{ "bab", UnboundedBranchAndBound },
+    }
+    kps := []KnapsackData{		// large capacity: periodic value function
+	{ Name: "large capacity", Dim: 3, P: []int{15,17,20}, W: []int{7,9,11}, C: 1000 },
+	{ Name: "large capacity, remainder", Dim: 3, P: []int{15,17,20}, W: []int{7,9,11}, C: 1003 },
+    }
+    for _,kp := range testProblems(t) {
+	if kp.Dim > 6 {
+	    kp.Dim = 6
+	    kp.P, kp.W = kp.P[:6], kp.W[:6]
+	}
+	if kp.C > 60 {
+	    kp.C = 60
+	}
+	kps = append(kps, kp)
+    }
+    unbounded := func(i int) int { return int(^uint(0) >> 1) }
+
+    for _,kp := range kps {
+	if err := CheckPositiveWeights(kp); err != nil {
+	    t.Fatal(err)
+	}
+	zopt := enumerateMultiple(kp, unbounded)
+	value := func(x []int) int { return objectiveMultiple(kp, unbounded, x) }
+	for _,s := range solvers {
+	    x,z,err := s.solve(kp)
+	    if err != nil {
+		t.Fatalf("%s, %s: %v", s.name, kp.Name, err)
+	    }
+	    checkSolution(t, s.name + ", " + kp.Name, value, x, z, zopt, true)
+	}
+    }
+}
+
+func TestUndominatedItems(t *testing.T) {
+    tests := []struct {
+	kp    KnapsackData
+	items []int
+    }{
+	{ KnapsackData{ Dim: 3, P: []int{10,9,20}, W: []int{2,2,4} }, []int{0} },	// 2 copies of item 0
+	{ KnapsackData{ Dim: 3, P: []int{10,21,10}, W: []int{2,4,2} }, []int{0,1} },	// identical items
+	{ KnapsackData{ Dim: 2, P: []int{3,7}, W: []int{2,5} }, []int{0,1} },
+    }
+    for _,test := range tests {
+	if items := UndominatedItems(test.kp); fmt.Sprint(items) != fmt.Sprint(test.items) {
+	    t.Errorf("%v, %v: undominated items %v instead of %v", test.kp.P, test.kp.W, items, test.items)
+	}
+    }
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+    "errors"
+    "fmt"
     "os"
     "time"
 
@@ -36,29 +38,219 @@ func main() {
 	    Name: "indent",
 	    Usage: "produce neatly indented JSON output",
 	},
+	cli.BoolFlag{
+	    Name: "improve",
+	    Usage: "improve the solution by local search",
+	},
     }
     app.Commands = []cli.Command{
 	{
 	    Name: "bab",
 	    Usage: "Solve knapsack problem by branch and bound (A*)",
+	    Flags: babFlags,
 	    Action: func(c *cli.Context) error {
-	        return solve(c, func(p kp.KnapsackProblem) ([]int,int) { return kp.BranchAndBound(p) })
+		if k := c.Int("kbest"); k > 0 {
+		    if c.Bool("exact") {
+			return errors.New("--exact is not supported with --kbest")
+		    }
+		    return solveAll(c, func(p kp.KnapsackProblem) ([]kp.Solution,error) { return kp.KBestBab(p, k) })
+		}
+		return solveBab(c, kp.BranchAndBoundOpt)
 	    },
         },
 	{
 	    Name: "hs",
 	    Usage: "Solve knapsack problem by branch and bound algorithm of Horowitz and Sahni",
+	    Flags: babFlags,
 	    Action: func(c *cli.Context) error {
-	        return solve(c, func(p kp.KnapsackProblem) ([]int,int) { return kp.BranchAndBoundHS(p) })
+		if k := c.Int("kbest"); k > 0 {
+		    if c.Bool("exact") {
+			return errors.New("--exact is not supported with --kbest")
+		    }
+		    return solveAll(c, func(p kp.KnapsackProblem) ([]kp.Solution,error) { return kp.KBestHS(p, k) })
+		}
+		return solveBab(c, kp.BranchAndBoundHSOpt)
 	    },
         },
 	{
 	    Name: "dp",
 	    Usage: "Solve knapsack problem by dynamic programming",
+	    Flags: []cli.Flag{
+		cli.BoolFlag{
+		    Name: "all",
+		    Usage: "enumerate all optimal solutions",
+		},
+		cli.IntFlag{
+		    Name: "max",
+		    Value: 1000,
+		    Usage: "maximal number of enumerated solutions (0: no limit)",
+		},
+		cli.BoolFlag{
+		    Name: "cert",
+		    Usage: "write an optimality certificate (the value function)",
+		},
+		cli.BoolFlag{
+		    Name: "exact",
+		    Usage: "only solutions which fill the capacity exactly are feasible (not with --all and --cert)",
+		},
+	    },
 	    Action: func(c *cli.Context) error {
+		if c.Bool("exact") && (c.Bool("all") || c.Bool("cert")) {
+		    return errors.New("--exact is not supported with --all and --cert")
+		}
+		if c.Bool("all") {
+		    return solveAll(c, func(p kp.KnapsackProblem) ([]kp.Solution,error) {
+			xs,z,err := kp.EnumOptimal(p, c.Int("max"))
+			sols := make([]kp.Solution, len(xs))
+			for i,x := range xs {
+			    sols[i] = kp.Solution{ X: x, Z: z }
+			}
+			return sols,err
+		    })
+		}
+		if c.Bool("cert") {
+		    return solveErr(c, func(kpp *kp.KnapsackData) ([]int,int,error) {
+			x,z,cert,err := kp.DynProgCertificate(kpp)
+			kpp.Certificate = &cert
+			return x,z,err
+		    })
+		}
+		if c.Bool("exact") {
+		    return solveErr(c, func(kpp *kp.KnapsackData) ([]int,int,error) { return kp.DynProgExact(kpp) })
+		}
 	        return solve(c, func(p kp.KnapsackProblem) ([]int,int) { return kp.DynProg(p) })
 	    },
 	},
+	{
+	    Name: "bkp",
+	    Usage: "Solve bounded knapsack problem (bounds give the number of copies of each item)",
+	    Flags: []cli.Flag{
+		cli.BoolFlag{
+		    Name: "bab",
+		    Usage: "use branch and bound on the binary split problem instead of dynamic programming",
+		},
+	    },
+	    Action: func(c *cli.Context) error {
+		if c.Bool("bab") {
+		    return solveBounded(c, kp.BoundedBranchAndBound)
+		}
+		return solveBounded(c, kp.BoundedDynProg)
+	    },
+	},
+	{
+	    Name: "ukp",
+	    Usage: "Solve unbounded knapsack problem (every item may be selected any number of times)",
+	    Flags: []cli.Flag{
+		cli.BoolFlag{
+		    Name: "bab",
+		    Usage: "use branch and bound (MTU2) instead of dynamic programming",
+		},
+	    },
+	    Action: func(c *cli.Context) error {
+		if c.Bool("bab") {
+		    return solveUnbounded(c, kp.UnboundedBranchAndBound)
+		}
+		return solveUnbounded(c, kp.UnboundedDynProg)
+	    },
+	},
+	{
+	    Name: "mckp",
+	    Usage: "Solve multiple-choice knapsack problem (exactly one item of each class)",
+	    Flags: []cli.Flag{
+		cli.BoolFlag{
+		    Name: "bab",
+		    Usage: "use branch and bound instead of dynamic programming",
+		},
+		cli.BoolFlag{
+		    Name: "ub",
+		    Usage: "compute only an upper bound by the LP relaxation",
+		},
+	    },
+	    Action: func(c *cli.Context) error {
+		if c.Bool("ub") {
+		    return solveMC(c, func(kpp *kp.KnapsackData) error {
+			var err error
+			kpp.Xf, kpp.Z, err = kp.MCUpperBound(kpp)
+			return err
+		    })
+		}
+		mcfunc := kp.MCDynProg
+		if c.Bool("bab") {
+		    mcfunc = kp.MCBranchAndBound
+		}
+		return solveMC(c, func(kpp *kp.KnapsackData) error {
+		    var err error
+		    kpp.X, kpp.Z, err = mcfunc(kpp)
+		    if err != nil {
+			return err
+		    }
+		    kpp.Choice = kp.MCChoice(kpp, kpp.X)
+		    return nil
+		})
+	    },
+	},
+	{
+	    Name: "mkp",
+	    Usage: "Solve multiple knapsack problem (several knapsacks with the given capacities)",
+	    Flags: []cli.Flag{
+		cli.BoolFlag{
+		    Name: "heuristic",
+		    Usage: "use the greedy heuristic instead of bound and bound",
+		},
+	    },
+	    Action: func(c *cli.Context) error {
+		if c.Bool("heuristic") {
+		    return solveMKP(c, kp.MKPGreedy)
+		}
+		return solveMKP(c, kp.MKPBoundAndBound)
+	    },
+	},
+	{
+	    Name: "mdkp",
+	    Usage: "Solve multidimensional knapsack problem (weights matrix and capacities)",
+	    Flags: []cli.Flag{
+		cli.BoolFlag{
+		    Name: "heuristic",
+		    Usage: "use the primal-dual heuristic instead of branch and bound",
+		},
+		cli.BoolFlag{
+		    Name: "ub",
+		    Usage: "compute only an upper bound by the surrogate relaxation",
+		},
+	    },
+	    Action: solveMD,
+	},
+	{
+	    Name: "ssp",
+	    Usage: "Solve subset-sum problem (largest sum of weights up to the capacity)",
+	    Flags: []cli.Flag{
+		cli.BoolFlag{
+		    Name: "exact",
+		    Usage: "fail if no subset fills the capacity exactly",
+		},
+	    },
+	    Action: solveSubsetSum,
+	},
+	{
+	    Name: "change",
+	    Usage: "Solve change-making problem (minimal number of coins for an amount)",
+	    Action: solveChange,
+	},
+	{
+	    Name: "qkp",
+	    Usage: "Solve quadratic knapsack problem (additional profits of item pairs)",
+	    Flags: []cli.Flag{
+		cli.BoolFlag{
+		    Name: "heuristic",
+		    Usage: "use the greedy heuristic with exchanges instead of branch and bound",
+		},
+		cli.BoolFlag{
+		    Name: "ub",
+		    Usage: "compute only an upper bound by the upper plane relaxation",
+		},
+	    },
+	    Action: solveQKP,
+	},
 	{
 	    Name: "greedy",
 	    Usage: "Solve knapsack problem by greedy heuristic",
@@ -66,6 +258,163 @@ func main() {
 	        return solve(c, func(p kp.KnapsackProblem) ([]int,int) { return kp.Greedy(p) })
 	    },
 	},
+	{
+	    Name: "dualgreedy",
+	    Usage: "Solve knapsack problem by dual greedy heuristic",
+	    Action: func(c *cli.Context) error {
+	        return solve(c, func(p kp.KnapsackProblem) ([]int,int) { return kp.DualGreedy(p) })
+	    },
+	},
+	{
+	    Name: "extgreedy",
+	    Usage: "Solve knapsack problem by extended greedy heuristic (1/2-approximation)",
+	    Action: func(c *cli.Context) error {
+	        return solve(c, func(p kp.KnapsackProblem) ([]int,int) { return kp.ExtGreedy(p) })
+	    },
+	},
+	{
+	    Name: "sa",
+	    Usage: "Solve knapsack problem by simulated annealing",
+	    Flags: []cli.Flag{
+		cli.IntFlag{
+		    Name: "iterations",
+		    Value: 100000,
+		    Usage: "iteration budget",
+		},
+		cli.Float64Flag{
+		    Name: "t0",
+		    Usage: "initial temperature (default: maximal profit)",
+		},
+		cli.StringFlag{
+		    Name: "cooling",
+		    Value: "geometric",
+		    Usage: "cooling schedule: geometric, linear or logarithmic",
+		},
+		cli.Float64Flag{
+		    Name: "alpha",
+		    Usage: "cooling factor for geometric cooling",
+		},
+		cli.StringFlag{
+		    Name: "repair",
+		    Value: "ratio",
+		    Usage: "repair operator for infeasible moves: ratio, random or reject",
+		},
+		cli.Int64Flag{
+		    Name: "seed",
+		    Usage: "seed value for the random generator, 0: time based seed",
+		},
+	    },
+	    Action: func(c *cli.Context) error {
+		par := kp.AnnealParams{
+		    Iterations: c.Int("iterations"),
+		    T0: c.Float64("t0"),
+		    Cooling: c.String("cooling"),
+		    Alpha: c.Float64("alpha"),
+		    Repair: c.String("repair"),
+		    Seed: c.Int64("seed"),
+		}
+		return solveErr(c, func(kpp *kp.KnapsackData) ([]int,int,error) { return kp.SimAnneal(kpp, par) })
+	    },
+	},
+	{
+	    Name: "ga",
+	    Usage: "Solve knapsack problem by a genetic algorithm",
+	    Flags: []cli.Flag{
+		cli.IntFlag{
+		    Name: "popsize",
+		    Value: 100,
+		    Usage: "population size",
+		},
+		cli.IntFlag{
+		    Name: "generations",
+		    Value: 500,
+		    Usage: "number of generations",
+		},
+		cli.IntFlag{
+		    Name: "tournament",
+		    Value: 2,
+		    Usage: "tournament size",
+		},
+		cli.Float64Flag{
+		    Name: "mutation",
+		    Usage: "mutation probability of a single bit (default: 1/n)",
+		},
+		cli.IntFlag{
+		    Name: "workers",
+		    Usage: "number of goroutines for the fitness evaluation (default: number of CPUs)",
+		},
+		cli.Int64Flag{
+		    Name: "seed",
+		    Usage: "seed value for the random generator, 0: time based seed",
+		},
+	    },
+	    Action: func(c *cli.Context) error {
+		par := kp.GeneticParams{
+		    PopSize: c.Int("popsize"),
+		    Generations: c.Int("generations"),
+		    Tournament: c.Int("tournament"),
+		    Mutation: c.Float64("mutation"),
+		    Workers: c.Int("workers"),
+		    Seed: c.Int64("seed"),
+		}
+		return solveErr(c, func(kpp *kp.KnapsackData) ([]int,int,error) { return kp.Genetic(kpp, par) })
+	    },
+	},
+	{
+	    Name: "tabu",
+	    Usage: "Solve knapsack problem by tabu search",
+	    Flags: []cli.Flag{
+		cli.IntFlag{
+		    Name: "iterations",
+		    Value: 1000,
+		    Usage: "iteration budget",
+		},
+		cli.IntFlag{
+		    Name: "tenure",
+		    Usage: "number of iterations a flipped item stays tabu (default: n/4)",
+		},
+	    },
+	    Action: func(c *cli.Context) error {
+		par := kp.TabuParams{
+		    Iterations: c.Int("iterations"),
+		    Tenure: c.Int("tenure"),
+		}
+		return solveErr(c, func(kpp *kp.KnapsackData) ([]int,int,error) { return kp.TabuSearch(kpp, par) })
+	    },
+	},
+	{
+	    Name: "grasp",
+	    Usage: "Solve knapsack problem by GRASP (randomized greedy with local search)",
+	    Flags: []cli.Flag{
+		cli.IntFlag{
+		    Name: "restarts",
+		    Value: 100,
+		    Usage: "number of constructed solutions",
+		},
+		cli.IntFlag{
+		    Name: "rcl",
+		    Value: 3,
+		    Usage: "size of the restricted candidate list",
+		},
+		cli.IntFlag{
+		    Name: "workers",
+		    Usage: "number of goroutines (default: number of CPUs)",
+		},
+		cli.Int64Flag{
+		    Name: "seed",
+		    Usage: "seed value for the random generator, 0: time based seed",
+		},
+	    },
+	    Action: func(c *cli.Context) error {
+		par := kp.GraspParams{
+		    Restarts: c.Int("restarts"),
+		    RCLSize: c.Int("rcl"),
+		    Workers: c.Int("workers"),
+		    Seed: c.Int64("seed"),
+		}
+		return solveErr(c, func(kpp *kp.KnapsackData) ([]int,int,error) { return kp.Grasp(kpp, par) })
+	    },
+	},
 	{
 	    Name: "ub",
 	    Usage: "Compute an upper bound for the objective function value of a knapsack problem",
@@ -73,6 +422,27 @@ func main() {
 	        return bound(c, func(p kp.KnapsackProblem) ([]float64,int) { return kp.UpperBound(p) })
 	    },
 	},
+	{
+	    Name: "sensitivity",
+	    Usage: "Sensitivity analysis of the profits for an optimal solution (given by x or computed by dynamic programming)",
+	    Action: sensitivity,
+	},
+	{
+	    Name: "capcurve",
+	    Usage: "Compute the optimal value for every capacity from 0 to C",
+	    Flags: []cli.Flag{
+		cli.BoolFlag{
+		    Name: "breakpoints",
+		    Usage: "write only the breakpoints of the curve",
+		},
+	    },
+	    Action: capcurve,
+	},
+	{
+	    Name: "verify",
+	    Usage: "Verify the optimality of x by the certificate of the input (without solving)",
+	    Action: verify,
+	},
 	{
 	    Name: "gen",
 	    Usage: "Generate a knapsack problem instance",
@@ -83,7 +453,107 @@ func main() {
     app.Run(os.Args)
 }
 
+// Flags of the branch and bound commands
+var babFlags = []cli.Flag{
+    cli.StringFlag{
+	Name: "checkpoint,k",
+	Value: "",
+	Usage: "write checkpoints of the search to this file",
+    },
+    cli.IntFlag{
+	Name: "interval",
+	Value: 60,
+	Usage: "seconds between two checkpoints",
+    },
+    cli.BoolFlag{
+	Name: "resume",
+	Usage: "resume the search from the checkpoint file",
+    },
+    cli.IntFlag{
+	Name: "kbest",
+	Usage: "compute the k best solutions instead of one optimal solution",
+    },
+    cli.BoolFlag{
+	Name: "nowarm",
+	Usage: "start without incumbent (default: x of the input or extended greedy solution)",
+    },
+    cli.BoolFlag{
+	Name: "savings",
+	Usage: "solve once more without warm start and report the number of saved nodes (doubles the runtime, may be negative for bab)",
+    },
+    cli.BoolFlag{
+	Name: "cert",
+	Usage: "write an optimality certificate (the search tree)",
+    },
+    cli.BoolFlag{
+	Name: "exact",
+	Usage: "only solutions which fill the capacity exactly are feasible (not with --kbest and --cert)",
+    },
+}
+
+func babOptions(c *cli.Context) kp.BabOptions {
+    return kp.BabOptions{
+	CheckpointFile: c.String("checkpoint"),
+	CheckpointInterval: time.Duration(c.Int("interval")) * time.Second,
+	Resume: c.Bool("resume"),
+	NoWarmStart: c.Bool("nowarm"),
+	ExactFill: c.Bool("exact"),
+    }
+}
+
+// Solve by branch and bound. If the input contains a solution x, it is used
+// as initial incumbent. An invalid x (e.g. of an older version of the
+// problem) is ignored with a warning and the default incumbent is used.
+func solveBab(c *cli.Context, babfunc func(p kp.KnapsackProblem, opt kp.BabOptions) ([]int,int,error)) error {
+    return solveErr(c, func(kpp *kp.KnapsackData) ([]int,int,error) {
+	var stats kp.BabStats
+
+	opt := babOptions(c)
+	if kpp.X != nil {
+	    if _,err := kp.CheckIncumbent(kpp, kpp.X, opt.ExactFill); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: x of the input is ignored: %v\n", err)
+	    } else {
+		opt.Incumbent = kpp.X
+	    }
+	}
+	opt.Stats = &stats
+	if c.Bool("cert") {
+	    opt.Certificate = &kp.Certificate{}
+	}
+	x,z,err := babfunc(kpp, opt)
+	if err != nil {
+	    return nil,0,err
+	}
+	kpp.Nodes = stats.Nodes
+	kpp.Certificate = opt.Certificate
+
+	// The savings need a second run from cold, which takes about as long as
+	// the first one. A* doesn't profit from a warm start (see BranchAndBoundOpt()),
+	// its saving is small and may be negative.
+	if c.Bool("savings") {		// solve again without warm start
+	    var cold kp.BabStats
+	    _,_,err = babfunc(kpp, kp.BabOptions{ NoWarmStart: true, Stats: &cold, ExactFill: opt.ExactFill })
+	    if err != nil {
+		return nil,0,err
+	    }
+	    saved := cold.Nodes - stats.Nodes
+	    if saved < 0 {
+		fmt.Fprintf(os.Stderr, "note: the warm start generated %v more nodes\n", -saved)
+	    }
+	    kpp.NodesSaved = &saved
+	}
+	return x,z,nil
+    })
+}
+
 func solve(c *cli.Context, solvfunc func(p kp.KnapsackProblem) ([]int,int)) error {
+    return solveErr(c, func(kpp *kp.KnapsackData) ([]int,int,error) {
+	x,z := solvfunc(kpp)
+	return x,z,nil
+    })
+}
+
+func solveErr(c *cli.Context, solvfunc func(kpp *kp.KnapsackData) ([]int,int,error)) error {
     var (
         kpp kp.KnapsackData
 	err error
@@ -105,12 +575,308 @@ func solve(c *cli.Context, solvfunc func(p kp.KnapsackProblem) ([]int,int)) erro
         return err
     }
 
-    x,z := solvfunc(kpp)		// solve
-    kpp.X = x
-    kpp.Z = z
+    red, sub, err := reduce(&kpp)	// fixed items
+    if err != nil {
+	return err
+    }
+
+    x,z,err := solvfunc(&sub)		// solve
+    if err != nil {
+	return err
+    }
+    if c.GlobalBool("improve") && !c.Bool("exact") {	// improve (local search
+	x,z,err = kp.LocalSearch(sub, x)		// may break an exact fill)
+	if err != nil {
+	    return err
+	}
+    }
+    kpp.X, kpp.Z = red.Expand(x,z)
+    kpp.Nodes = sub.Nodes
+    kpp.NodesSaved = sub.NodesSaved
+    kpp.Certificate = sub.Certificate
+    if kpp.Certificate != nil && hasFixedItems(&kpp) {	// the certificate refers to
+	kpp.Certificate.Fix = kpp.Fix			// the free items
+    }
+
+    return writeKnapsackProblem(&kpp, c)	// write
+
+}
+
+// Solve a bounded knapsack problem. Items without bounds exist only once.
+func solveBounded(c *cli.Context, solvfunc func(p kp.BoundedKnapsackProblem) ([]int,int,error)) error {
+    var (
+	kpp kp.KnapsackData
+	err error
+    )
+
+    err = readData(&kpp, c)		// read
+    if err != nil {
+	return err
+    }
+
+    err = kp.CheckBounds(&kpp)		// check
+    if err != nil {
+	return err
+    }
+    err = kp.CheckSortedItems(&kpp)
+    if err != nil {
+	return err
+    }
+    if kpp.Classes != nil {
+	return errors.New("bounded knapsack problems don't support classes")
+    }
+
+    kpp.X, kpp.Z, err = solvfunc(kpp)	// solve (fixed items are rejected)
+    if err != nil {
+	return err
+    }
+
+    return writeKnapsackProblem(&kpp, c)	// write
+}
+
+// Solve an unbounded knapsack problem. The bounds must be omitted.
+func solveUnbounded(c *cli.Context, solvfunc func(p kp.KnapsackProblem) ([]int,int,error)) error {
+    var (
+	kpp kp.KnapsackData
+	err error
+    )
+
+    err = readData(&kpp, c)		// read
+    if err != nil {
+	return err
+    }
+
+    err = kp.CheckPositiveWeights(&kpp)	// check
+    if err != nil {
+	return err
+    }
+    err = kp.CheckSortedItems(&kpp)
+    if err != nil {
+	return err
+    }
+    if kpp.Bounds != nil {
+	return errors.New("unbounded knapsack problems don't support bounds")
+    }
+    if kpp.Classes != nil {
+	return errors.New("unbounded knapsack problems don't support classes")
+    }
+
+    kpp.X, kpp.Z, err = solvfunc(kpp)	// solve (fixed items are rejected)
+    if err != nil {
+	return err
+    }
+
+    return writeKnapsackProblem(&kpp, c)	// write
+}
+
+// Solve a multiple-choice knapsack problem, solvfunc stores the results in kpp.
+func solveMC(c *cli.Context, solvfunc func(kpp *kp.KnapsackData) error) error {
+    var (
+	kpp kp.KnapsackData
+	err error
+    )
+
+    err = readData(&kpp, c)		// read
+    if err != nil {
+	return err
+    }
+
+    err = kp.CheckClasses(&kpp)		// check
+    if err != nil {
+	return err
+    }
+    if kpp.Bounds != nil {
+	return errors.New("multiple-choice knapsack problems don't support bounds")
+    }
+
+    err = solvfunc(&kpp)		// solve (fixed items are rejected)
+    if err != nil {
+	return err
+    }
+
+    return writeKnapsackProblem(&kpp, c)	// write
+}
+
+// Solve a multiple knapsack problem. The result is the assignment of the
+// items to the knapsacks, x marks the packed items.
+func solveMKP(c *cli.Context, solvfunc func(p kp.MultipleKnapsackProblem) ([]int,int,error)) error {
+    var (
+	kpp kp.KnapsackData
+	err error
+    )
+
+    err = readData(&kpp, c)		// read
+    if err != nil {
+	return err
+    }
+
+    err = kp.CheckCapacities(&kpp)	// check
+    if err != nil {
+	return err
+    }
+    err = kp.CheckSortedItems(&kpp)
+    if err != nil {
+	return err
+    }
+    if kpp.Bounds != nil || kpp.Classes != nil {
+	return errors.New("multiple knapsack problems don't support bounds or classes")
+    }
+
+    kpp.Assignment, kpp.Z, err = solvfunc(kpp)	// solve (fixed items are rejected)
+    if err != nil {
+	return err
+    }
+    kpp.X = make([]int, kpp.Dim)
+    for i,j := range kpp.Assignment {
+	if j >= 0 {
+	    kpp.X[i] = 1
+	}
+    }
 
     return writeKnapsackProblem(&kpp, c)	// write
+}
+
+func solveMD(c *cli.Context) error {
+    var (
+	kpp kp.MDKnapsackData
+	err error
+    )
+
+    err = readData(&kpp, c)		// read
+    if err != nil {
+	return err
+    }
+
+    err = kp.CheckMDData(&kpp)		// check
+    if err != nil {
+	return err
+    }
+
+    switch {				// solve
+    case c.Bool("ub"):
+	kpp.Xf, kpp.Z = kp.MDUpperBound(kpp)
+    case c.Bool("heuristic"):
+	kpp.X, kpp.Z = kp.MDPrimalDual(kpp)
+    default:
+	kpp.X, kpp.Z = kp.MDBranchAndBound(kpp)
+    }
+
+    return writeData(&kpp, c)		// write
+}
+
+// Solve a subset-sum problem. With --exact it is an error, if the largest
+// sum doesn't reach the capacity.
+func solveSubsetSum(c *cli.Context) error {
+    var (
+	kpp kp.SubsetSumData
+	err error
+    )
+
+    err = readData(&kpp, c)		// read
+    if err != nil {
+	return err
+    }
+
+    err = kp.CheckSubsetSumData(&kpp)	// check
+    if err != nil {
+	return err
+    }
+
+    kpp.X, kpp.Z = kp.SubsetSum(kpp)	// solve
+    if c.Bool("exact") && kpp.Z != kpp.C {
+	return fmt.Errorf("infeasible: the largest sum up to the capacity is %v", kpp.Z)
+    }
 
+    return writeData(&kpp, c)		// write
+}
+
+// Solve a change-making problem.
+func solveChange(c *cli.Context) error {
+    var (
+	cm  kp.ChangeData
+	err error
+    )
+
+    err = readData(&cm, c)		// read
+    if err != nil {
+	return err
+    }
+
+    err = kp.CheckChangeData(&cm)	// check
+    if err != nil {
+	return err
+    }
+
+    cm.X, cm.Z, err = kp.ChangeMaking(cm)	// solve
+    if err != nil {
+	return err
+    }
+
+    return writeData(&cm, c)		// write
+}
+
+func solveQKP(c *cli.Context) error {
+    var (
+	kpp kp.QKnapsackData
+	err error
+    )
+
+    err = readData(&kpp, c)		// read
+    if err != nil {
+	return err
+    }
+
+    err = kp.CheckQKPData(&kpp)		// check
+    if err != nil {
+	return err
+    }
+
+    switch {				// solve
+    case c.Bool("ub"):
+	kpp.Z = kp.QKPUpperBound(kpp)
+    case c.Bool("heuristic"):
+	kpp.X, kpp.Z = kp.QKPGreedy(kpp)
+    default:
+	kpp.X, kpp.Z = kp.QKPBranchAndBound(kpp)
+    }
+
+    return writeData(&kpp, c)		// write
+}
+
+func solveAll(c *cli.Context, solvfunc func(p kp.KnapsackProblem) ([]kp.Solution,error)) error {
+    var (
+	kpp kp.KnapsackData
+	err error
+    )
+
+    err = readData(&kpp, c)		// read
+    if err != nil {
+	return err
+    }
+
+    err = kp.CheckSortedItems(&kpp)
+    if err != nil {
+	return err
+    }
+
+    red, sub, err := reduce(&kpp)	// fixed items
+    if err != nil {
+	return err
+    }
+
+    kpp.Solutions, err = solvfunc(sub)	// solve
+    if err != nil {
+	return err
+    }
+    for i,sol := range kpp.Solutions {
+	kpp.Solutions[i].X, kpp.Solutions[i].Z = red.Expand(sol.X, sol.Z)
+    }
+    if len(kpp.Solutions) > 0 {		// the first solution is the best one
+	kpp.X = kpp.Solutions[0].X
+	kpp.Z = kpp.Solutions[0].Z
+    }
+
+    return writeKnapsackProblem(&kpp, c)	// write
 }
 
 func bound(c *cli.Context, ubfunc func(p kp.KnapsackProblem) ([]float64,int)) error {
@@ -135,9 +901,125 @@ func bound(c *cli.Context, ubfunc func(p kp.KnapsackProblem) ([]float64,int)) er
         return err
     }
 
-    x,z := ubfunc(kpp)			// solve
-    kpp.Xf = x
-    kpp.Z = z
+    red, sub, err := reduce(&kpp)	// fixed items
+    if err != nil {
+	return err
+    }
+
+    x,z := ubfunc(sub)			// solve
+    kpp.Xf, kpp.Z = red.ExpandFrac(x,z)
+    _,lp,err := kp.LPRelax(sub)
+    if err != nil {
+	return err
+    }
+    lp = red.ExpandLP(lp)
+    kpp.LP = &lp
+
+    return writeKnapsackProblem(&kpp, c)	// write
+}
+
+func sensitivity(c *cli.Context) error {
+    var (
+	kpp kp.KnapsackData
+	err error
+    )
+
+    err = readData(&kpp, c)		// read
+    if err != nil {
+	return err
+    }
+
+    err = check01(&kpp)
+    if err != nil {
+	return err
+    }
+    err = kp.CheckFixedItems(&kpp)
+    if err != nil {
+	return err
+    }
+
+    if kpp.X == nil {			// solve, if the input contains no solution
+	kpp.X, kpp.Z = kp.DynProg(kpp)
+    }
+    kpp.Sensitivity, err = kp.Sensitivity(kpp, kpp.X)	// analyze
+    if err != nil {
+	return err
+    }
+
+    return writeKnapsackProblem(&kpp, c)	// write
+}
+
+func capcurve(c *cli.Context) error {
+    var (
+	kpp kp.KnapsackData
+	err error
+    )
+
+    err = readData(&kpp, c)		// read
+    if err != nil {
+	return err
+    }
+
+    err = check01(&kpp)
+    if err != nil {
+	return err
+    }
+    err = kp.CheckFixedItems(&kpp)
+    if err != nil {
+	return err
+    }
+    if kpp.C < 0 {			// the curve is defined for 0,...,C
+	return errors.New("wrong input: negative capacity")
+    }
+    for _,w := range kpp.W {
+	if w < 0 {
+	    return errors.New("wrong input: negative weight")
+	}
+    }
+
+    curve := kp.CapacityCurve(kpp)	// solve
+    if c.Bool("breakpoints") {
+	kpp.Breakpoints = kp.CapacityBreakpoints(curve)
+    } else {
+	kpp.CapCurve = curve
+    }
+    kpp.Z = curve[kpp.C]
+
+    return writeKnapsackProblem(&kpp, c)	// write
+}
+
+// Verify the solution x of the input by its certificate. The input is
+// written unchanged if the certificate proves x optimal with value z,
+// otherwise an error is returned. A certificate of a problem with fixed items
+// must have the same fix markers.
+func verify(c *cli.Context) error {
+    var (
+	kpp kp.KnapsackData
+	err error
+    )
+
+    err = readData(&kpp, c)		// read
+    if err != nil {
+	return err
+    }
+    if kpp.X == nil {
+	return errors.New("wrong input: no solution x to verify")
+    }
+    if kpp.Certificate == nil {
+	return errors.New("wrong input: no certificate")
+    }
+
+    err = check01(&kpp)			// check
+    if err != nil {
+	return err
+    }
+    z, err := kp.CheckCertificate(kpp, kpp.X, kpp.Certificate)
+    if err != nil {
+	return err
+    }
+    if z != kpp.Z {
+	return fmt.Errorf("wrong input: z = %v, but x has the value %v", kpp.Z, z)
+    }
 
     return writeKnapsackProblem(&kpp, c)	// write
 }
@@ -164,6 +1046,48 @@ func generate(c *cli.Context) error {
     return writeKnapsackProblem(&kpp, c)
 }
 
+// The 0/1 solvers don't support the variants of the knapsack problem.
+func check01(kpp *kp.KnapsackData) error {
+    if kpp.Bounds != nil {
+	return errors.New("bounded knapsack problems are solved by the bkp command")
+    }
+    if kpp.Classes != nil {
+	return errors.New("multiple-choice knapsack problems are solved by the mckp command")
+    }
+    if kpp.Capacities != nil {
+	return errors.New("multiple knapsack problems are solved by the mkp command")
+    }
+    return nil
+}
+
+// Reduce a problem with fixed items to its free items. The reduced problem
+// contains the solution x of the input restricted to the free items, too.
+// An x of the wrong size is passed unchanged, the callers report it.
+func reduce(kpp *kp.KnapsackData) (*kp.Reduction, kp.KnapsackData, error) {
+    if err := check01(kpp); err != nil {
+	return nil, kp.KnapsackData{}, err
+    }
+    red, err := kp.Reduce(kpp, kpp.Fix)
+    if err != nil {
+	return nil, kp.KnapsackData{}, err
+    }
+    sub := red.Data()
+    sub.X = kpp.X
+    if len(kpp.X) == kpp.Dim {
+	sub.X = red.Restrict(kpp.X)
+    }
+    return red, sub, nil
+}
+
+func hasFixedItems(kpp *kp.KnapsackData) bool {
+    for _,f := range kpp.Fix {
+	if f != kp.FixFree {
+	    return true
+	}
+    }
+    return false
+}
+
 func readData(object interface{}, c *cli.Context) error {
     var (
 	r   *os.File
@@ -189,6 +1113,10 @@ func readData(object interface{}, c *cli.Context) error {
 }
 
 func writeKnapsackProblem(kpp *kp.KnapsackData, c *cli.Context) error {
+    return writeData(kpp, c)
+}
+
+func writeData(object interface{}, c *cli.Context) error {
     var (
 	w   *os.File
         err error
@@ -206,9 +1134,9 @@ func writeKnapsackProblem(kpp *kp.KnapsackData, c *cli.Context) error {
 
     indent := c.GlobalBool("indent")
     if indent {
-	err = s4a.WriteFJsonOutputIndent(w, &kpp, "", "    ")
+	err = s4a.WriteFJsonOutputIndent(w, object, "", "    ")
     } else {
-	err = s4a.WriteFJsonOutput(w, &kpp)
+	err = s4a.WriteFJsonOutput(w, object)
     }
     if err != nil {
         return  err